@@ -0,0 +1,161 @@
+/*
+Copyright 2013 Alessandro Frossi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package Benchmark implements the workloads used to measure the performance of the search algorithms
+// on large graphs and to catch regressions between versions.
+//
+// A workload is a graph, either generated or loaded from a DIMACS file, and a fixed set of queries drawn from its nodes
+// with a given seed, so that two runs of the same benchmark perform exactly the same searches. Run executes one query
+// per benchmark iteration and reports, besides the time and the allocations per query, the number of nodes settled
+// by each query, counted as the calls to SuccessorsForNode and PredecessorsFromNode. The connections of the workload
+// graphs are cached, so that the reported allocations are those of the search alone.
+package benchmark
+
+import (
+	"fmt"
+	"github.com/kirves/godijkstra/common/structs"
+	"github.com/kirves/godijkstra/generate"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+const (
+	QueryCount = 64  // Number of queries of the generated workloads
+	QuerySeed  = 1   // Seed of the queries of the generated workloads
+	MaxWeight  = 100 // Maximum edge weight of the generated grid and scale-free graphs
+)
+
+// Query is a single shortest path search from Start to End.
+type Query struct {
+	Start, End string
+}
+
+// NewQueries returns count queries between distinct random nodes among nodes, drawn with the given seed.
+func NewQueries(nodes []string, count int, seed int64) []Query {
+	ret := make([]Query, 0, count)
+	if len(nodes) < 2 {
+		return ret
+	}
+	r := rand.New(rand.NewSource(seed))
+	for len(ret) < count {
+		s, e := r.Intn(len(nodes)), r.Intn(len(nodes))
+		if s != e {
+			ret = append(ret, Query{nodes[s], nodes[e]})
+		}
+	}
+	return ret
+}
+
+// CountingGraph is a graph object counting the nodes expanded by the searches run on it.
+// Every call to SuccessorsForNode and PredecessorsFromNode is counted, including the ones made outside a search,
+// such as the lookups done by Yen to ban the edges leaving a root path.
+type CountingGraph struct {
+	dijkstrastructs.GraphObject
+	settled int
+}
+
+// NewCountingGraph returns a counting graph wrapping graph.
+func NewCountingGraph(graph dijkstrastructs.GraphObject) *CountingGraph {
+	return &CountingGraph{GraphObject: graph}
+}
+
+func (g *CountingGraph) SuccessorsForNode(node string) []dijkstrastructs.Connection {
+	g.settled++
+	return g.GraphObject.SuccessorsForNode(node)
+}
+
+func (g *CountingGraph) PredecessorsFromNode(node string) []dijkstrastructs.Connection {
+	g.settled++
+	return g.GraphObject.PredecessorsFromNode(node)
+}
+
+// Settled returns the number of nodes expanded since the graph was created or last reset.
+func (g *CountingGraph) Settled() int {
+	return g.settled
+}
+
+// Reset sets the number of expanded nodes back to zero.
+func (g *CountingGraph) Reset() {
+	g.settled = 0
+}
+
+// Workload is a graph with a fixed set of queries.
+type Workload struct {
+	Name    string
+	Graph   dijkstrastructs.GraphObject
+	Queries []Query
+}
+
+// NewWorkload returns a workload named name running QueryCount queries among the nodes of graph,
+// whose connections are cached.
+func NewWorkload(name string, graph *generate.Graph) Workload {
+	graph.CacheConnections()
+	return Workload{name, graph, NewQueries(graph.Nodes(), QueryCount, QuerySeed)}
+}
+
+// Generator builds a kind of workload graph of about n nodes.
+type Generator struct {
+	Kind  string
+	Build func(n int) *generate.Graph
+}
+
+// Generators lists the kinds of generated workloads: a grid with random weights, a road-like network
+// and a scale-free graph.
+var Generators = []Generator{
+	{"grid", func(n int) *generate.Graph {
+		side := int(math.Sqrt(float64(n)))
+		return generate.Grid(side, side, 1, MaxWeight, 1)
+	}},
+	{"road", func(n int) *generate.Graph {
+		side := int(math.Sqrt(float64(n)))
+		return generate.RoadLike(side, side, 1)
+	}},
+	{"scalefree", func(n int) *generate.Graph {
+		return generate.BarabasiAlbert(n, 3, 1, MaxWeight, 1)
+	}},
+}
+
+// Workload returns the workload of about n nodes built by gen.
+func (gen Generator) Workload(n int) Workload {
+	return NewWorkload(fmt.Sprintf("%s-%d", gen.Kind, n), gen.Build(n))
+}
+
+// Workloads returns the generated workloads of about n nodes, one for each of the Generators.
+func Workloads(n int) []Workload {
+	ret := make([]Workload, len(Generators))
+	for i, gen := range Generators {
+		ret[i] = gen.Workload(n)
+	}
+	return ret
+}
+
+// Run benchmarks search on the workload w, running one query per iteration and cycling over the queries,
+// so that the reported time and allocations are per query. The number of nodes settled per query is reported
+// as the settled/op metric, counted by a CountingGraph wrapping the workload graph.
+func Run(b *testing.B, w Workload, search func(graph dijkstrastructs.GraphObject, q Query)) {
+	if len(w.Queries) == 0 {
+		b.Skip("workload without queries")
+	}
+	g := NewCountingGraph(w.Graph)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		search(g, w.Queries[i%len(w.Queries)])
+	}
+	b.StopTimer()
+	b.ReportMetric(float64(g.Settled())/float64(b.N), "settled/op")
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2013 Alessandro Frossi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmark
+
+import (
+	"flag"
+	"fmt"
+	"github.com/kirves/godijkstra/common/path"
+	"github.com/kirves/godijkstra/common/structs"
+	"github.com/kirves/godijkstra/dijkstra"
+	"github.com/kirves/godijkstra/generate"
+	"github.com/kirves/godijkstra/yen"
+	"os"
+	"testing"
+)
+
+var (
+	large  = flag.Bool("large", false, "also run the benchmarks on graphs of 10^5 and 10^6 nodes")
+	dimacs = flag.String("dimacs", "", "also run the benchmarks on the DIMACS shortest path graph in `file`")
+)
+
+const yenK = 5
+
+// last and dimacsWorkload cache the workloads, which are expensive to build.
+// Only the last generated workload is kept, since the largest ones take hundreds of megabytes.
+var (
+	last           Workload
+	dimacsWorkload *Workload
+)
+
+func generatedWorkload(gen Generator, n int) Workload {
+	if name := fmt.Sprintf("%s-%d", gen.Kind, n); last.Name != name {
+		last = Workload{}
+		last = gen.Workload(n)
+	}
+	return last
+}
+
+func loadDIMACS(b *testing.B) Workload {
+	if dimacsWorkload == nil {
+		f, err := os.Open(*dimacs)
+		if err != nil {
+			b.Fatal(err)
+		}
+		defer f.Close()
+		g, err := generate.ReadDIMACS(f)
+		if err != nil {
+			b.Fatal(err)
+		}
+		w := NewWorkload("dimacs", g)
+		dimacsWorkload = &w
+	}
+	return *dimacsWorkload
+}
+
+func sizes() []int {
+	if *large {
+		return []int{10000, 100000, 1000000}
+	}
+	return []int{10000}
+}
+
+// benchmarkSearch runs search on the generated workloads of the given sizes and, if withDIMACS is set, on the DIMACS graph.
+func benchmarkSearch(b *testing.B, sizes []int, withDIMACS bool, search func(graph dijkstrastructs.GraphObject, q Query)) {
+	run := func(w Workload) {
+		b.Run(w.Name, func(b *testing.B) {
+			Run(b, w, search)
+		})
+	}
+	for _, n := range sizes {
+		for _, gen := range Generators {
+			run(generatedWorkload(gen, n))
+		}
+	}
+	if withDIMACS && *dimacs != "" {
+		run(loadDIMACS(b))
+	}
+}
+
+func BenchmarkDijkstra(b *testing.B) {
+	benchmarkSearch(b, sizes(), true, func(graph dijkstrastructs.GraphObject, q Query) {
+		dijkstra.Dijkstra(graph, q.Start, q.End, dijkstrastructs.EmptyUnusableEdgeMap())
+	})
+}
+
+func BenchmarkBiDirDijkstra(b *testing.B) {
+	benchmarkSearch(b, sizes(), true, func(graph dijkstrastructs.GraphObject, q Query) {
+		dijkstra.BiDirDijkstra(graph, q.Start, q.End, dijkstrastructs.EmptyUnusableEdgeMap())
+	})
+}
+
+// BenchmarkYen runs on the smallest generated graphs only: every query runs a search for each node of the k-1 first paths.
+// Its settled/op metric also counts the successor lookups made by Yen to ban the edges leaving the root paths.
+func BenchmarkYen(b *testing.B) {
+	benchmarkSearch(b, []int{10000}, false, func(graph dijkstrastructs.GraphObject, q Query) {
+		yen.Yen(graph, q.Start, q.End, yenK, func(g dijkstrastructs.GraphObject, s, e string, banned dijkstrastructs.UnusableEdgeMap) (dijkstrapath.DijkstraPath, bool) {
+			return dijkstra.BiDirDijkstra(g, s, e, banned)
+		})
+	})
+}
+
+func TestWorkloads(t *testing.T) {
+	ws, again := Workloads(100), Workloads(100)
+	for i, w := range ws {
+		if allocs := testing.AllocsPerRun(10, func() { w.Graph.SuccessorsForNode(w.Queries[0].Start) }); allocs != 0 {
+			t.Fatalf("%s: the graph allocates %v times per expansion", w.Name, allocs)
+		}
+		if len(w.Queries) != QueryCount {
+			t.Fatalf("%s: wrong number of queries: %d", w.Name, len(w.Queries))
+		}
+		for j, q := range w.Queries {
+			if q != again[i].Queries[j] {
+				t.Fatalf("%s: queries are not reproducible", w.Name)
+			}
+			if q.Start == q.End {
+				t.Fatalf("%s: query from a node to itself", w.Name)
+			}
+		}
+	}
+}
+
+func TestCountingGraph(t *testing.T) {
+	w := Workloads(100)[0]
+	g := NewCountingGraph(w.Graph)
+	q := w.Queries[0]
+	p, valid := dijkstra.Dijkstra(g, q.Start, q.End, dijkstrastructs.EmptyUnusableEdgeMap())
+	if !valid {
+		t.Fatalf("No path found from %s to %s", q.Start, q.End)
+	}
+	if g.Settled() < len(p.Path)-1 {
+		t.Fatalf("Too few settled nodes: %d for a path of %d nodes", g.Settled(), len(p.Path))
+	}
+	g.Reset()
+	if g.Settled() != 0 {
+		t.Fatalf("Counter not reset: %d", g.Settled())
+	}
+}
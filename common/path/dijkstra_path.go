@@ -22,9 +22,22 @@ import (
 )
 
 // Atmoic element of the path reperesented by the DijkstraPath struct
+// Path elements hold the attributes of their edge in a map, so they cannot be compared with == nor used as map keys.
 type DijkstraPathElement struct {
 	Node   string // Node name
 	Weight int    // Weight of the node (as computed by the Dijkstra algorithm)
+	EdgeID string // Identifier of the edge used to reach the node (if any)
+
+	EdgeAttributes map[string]interface{} // Attributes of the edge used to reach the node (if any)
+}
+
+// DijkstraPathEdge describes a step of a DijkstraPath, i.e. the edge going from a node of the path to the next one.
+type DijkstraPathEdge struct {
+	From       string                 // Source node
+	To         string                 // Destination node
+	ID         string                 // Edge identifier (if any)
+	Weight     int                    // Weight of the step
+	Attributes map[string]interface{} // Edge attributes (if any)
 }
 
 // The DijkstraPath structure saves all the information about the found path between source and destination
@@ -39,7 +52,7 @@ type DijkstraPath struct {
 }
 
 func newElementFromDijkstraCandidate(dc *dijkstrastructs.DijkstraCandidate) DijkstraPathElement {
-	return DijkstraPathElement{dc.Node, dc.Weight}
+	return DijkstraPathElement{dc.Node, dc.Weight, dc.EdgeID, dc.EdgeAttributes}
 }
 
 // ConvertToDijkstraPath creates a DijkstraPath from the CandidateSolution instance return by a run of the DijkstraAlgorithm.
@@ -54,6 +67,8 @@ func ConvertToDijkstraPath(cs dijkstrastructs.CandidateSolution, start, end stri
 	for dc = cs.BackCandidate.Parent; dc != nil; dc = dc.Parent {
 		item := newElementFromDijkstraCandidate(dc)
 		item.Weight = parent.Weight + (realParent.Weight - dc.Weight)
+		item.EdgeID = realParent.EdgeID
+		item.EdgeAttributes = realParent.EdgeAttributes
 		tmp = append(tmp, item)
 		parent = item
 		realParent = dc
@@ -71,7 +86,7 @@ func (dp DijkstraPath) IsEqual(p DijkstraPath) bool {
 		return false
 	}
 	for i, v := range dp.Path {
-		if v.Node != p.Path[i].Node {
+		if v.Node != p.Path[i].Node || v.EdgeID != p.Path[i].EdgeID {
 			return false
 		}
 	}
@@ -86,6 +101,19 @@ func appendForwardStepToDijkstraPath(dc *dijkstrastructs.DijkstraCandidate, path
 	return append(tmp, newElementFromDijkstraCandidate(dc))
 }
 
+// Edges returns the steps of the path, in order.
+func (dp DijkstraPath) Edges() []DijkstraPathEdge {
+	if len(dp.Path) == 0 {
+		return nil
+	}
+	ret := make([]DijkstraPathEdge, len(dp.Path)-1)
+	for i := 1; i < len(dp.Path); i++ {
+		prev, cur := dp.Path[i-1], dp.Path[i]
+		ret[i-1] = DijkstraPathEdge{prev.Node, cur.Node, cur.EdgeID, cur.Weight - prev.Weight, cur.EdgeAttributes}
+	}
+	return ret
+}
+
 func (dp DijkstraPath) computeWeight() int {
 	return dp.Path[len(dp.Path)-1].Weight
 }
@@ -114,7 +142,7 @@ func (dp DijkstraPath) includesPath(p DijkstraPath) bool {
 		return false
 	}
 	for i, v := range p.Path {
-		if dp.Path[i].Node != v.Node {
+		if dp.Path[i].Node != v.Node || dp.Path[i].EdgeID != v.EdgeID {
 			return false
 		}
 	}
@@ -129,6 +157,14 @@ func (dp DijkstraPath) OutgoingEdgeForSubPath(p DijkstraPath) []string {
 	return []string{dp.Path[edgeInd-1].Node, dp.Path[edgeInd].Node}
 }
 
+// OutgoingEdgeIDForSubPath returns the identifier of the edge returned by OutgoingEdgeForSubPath.
+func (dp DijkstraPath) OutgoingEdgeIDForSubPath(p DijkstraPath) string {
+	if !dp.includesPath(p) {
+		return ""
+	}
+	return dp.Path[len(p.Path)].EdgeID
+}
+
 func (dp DijkstraPath) MergeWith(p DijkstraPath) DijkstraPath {
 	ret := DijkstraPath{}
 	ret.Path = make([]DijkstraPathElement, len(dp.Path))
@@ -43,3 +43,51 @@ func (pq *DijkstraPathQueue) Pop() interface{} {
 	*pq = old[0 : n-1]
 	return x
 }
+
+// OrderedDijkstraPathQueue is a DijkstraPathQueue that breaks ties deterministically:
+// paths with the same weight are extracted by increasing number of nodes, then by comparing
+// their nodes (and edge identifiers) in order.
+// It implements the heap.Interface interface
+type OrderedDijkstraPathQueue []DijkstraPath
+
+func (pq OrderedDijkstraPathQueue) Len() int {
+	return len(pq)
+}
+
+func (pq OrderedDijkstraPathQueue) Less(i, j int) bool {
+	return lessPath(pq[i], pq[j])
+}
+
+func (pq OrderedDijkstraPathQueue) Swap(i, j int) {
+	pq[i], pq[j] = pq[j], pq[i]
+}
+
+func (pq *OrderedDijkstraPathQueue) Push(x interface{}) {
+	*pq = append(*pq, x.(DijkstraPath))
+}
+
+func (pq *OrderedDijkstraPathQueue) Pop() interface{} {
+	old := *pq
+	n := len(old)
+	x := old[n-1]
+	*pq = old[0 : n-1]
+	return x
+}
+
+func lessPath(a, b DijkstraPath) bool {
+	if a.Weight != b.Weight {
+		return a.Weight < b.Weight
+	}
+	if len(a.Path) != len(b.Path) {
+		return len(a.Path) < len(b.Path)
+	}
+	for i := range a.Path {
+		if a.Path[i].Node != b.Path[i].Node {
+			return a.Path[i].Node < b.Path[i].Node
+		}
+		if a.Path[i].EdgeID != b.Path[i].EdgeID {
+			return a.Path[i].EdgeID < b.Path[i].EdgeID
+		}
+	}
+	return false
+}
@@ -222,9 +222,23 @@ func ExampleDijkstraIterator_value() {
 	}
 
 	// Output:
-	// [{START 0}]
-	// [{START 0} {A 1}]
-	// [{START 0} {A 1} {B 2}]
-	// [{START 0} {A 1} {B 2} {C 3}]
-	// [{START 0} {A 1} {B 2} {C 3} {D 4}]
+	// [{START 0  map[]}]
+	// [{START 0  map[]} {A 1  map[]}]
+	// [{START 0  map[]} {A 1  map[]} {B 2  map[]}]
+	// [{START 0  map[]} {A 1  map[]} {B 2  map[]} {C 3  map[]}]
+	// [{START 0  map[]} {A 1  map[]} {B 2  map[]} {C 3  map[]} {D 4  map[]}]
+}
+
+func ExampleDijkstraPath_edges() {
+	for _, e := range path2.Edges() {
+		fmt.Printf("%s->%s :: w = %d\n", e.From, e.To, e.Weight)
+	}
+
+	// Output:
+	// START->A :: w = 1
+	// A->B :: w = 1
+	// B->E :: w = 1
+	// E->F :: w = 1
+	// F->G :: w = 1
+	// G->END :: w = 1
 }
@@ -22,6 +22,10 @@ type DijkstraCandidate struct {
 	Node   string             // Name of the analyzed node
 	Parent *DijkstraCandidate // Parent in the candidate path (used for backtracking)
 	Weight int                // Weight of the path so far
+	Hops   int                // Number of edges of the path so far
+	EdgeID string             // Identifier of the edge linking Node and Parent (if any)
+
+	EdgeAttributes map[string]interface{} // Attributes of the edge linking Node and Parent (if any)
 }
 
 // CandidateSolution is a possibile complete path from source to destination in the provided graph.
@@ -32,15 +36,86 @@ type CandidateSolution struct {
 }
 
 // Connection is an outgoing edge from a given node to node Destination, having weight Weight
+// ID optionally identifies the edge, telling apart parallel edges between the same nodes,
+// while Attributes can carry any information about the edge into the found paths.
+// Since Attributes is a map, connections cannot be compared with == nor used as map keys.
 type Connection struct {
+	Destination string                 // Destination node
+	Weight      int                    // Edge weight
+	ID          string                 // Edge identifier (optional)
+	Attributes  map[string]interface{} // Edge attributes (optional)
+}
+
+// TimeDependentConnection is an outgoing edge from a given node to node Destination,
+// whose travel time depends on the departure time as described by Profile
+type TimeDependentConnection struct {
+	Destination string            // Destination node
+	Profile     TravelTimeProfile // Travel time profile
+}
+
+// ResourceConnection is an outgoing edge from a given node to node Destination, having weight Weight
+// and consuming the amount of each resource listed in Resources
+type ResourceConnection struct {
 	Destination string // Destination node
 	Weight      int    // Edge weight
+	Resources   []int  // Resource consumption
+}
+
+// MultiCriteriaConnection is an outgoing edge from a given node to node Destination,
+// having one weight for each optimization criterion
+type MultiCriteriaConnection struct {
+	Destination string // Destination node
+	Weights     []int  // Edge weights, one for each criterion
 }
 
-// UnusableEdgeMap is a list of "banned" edges used by the deviation algorithm
+// UnusableEdgeMap is a list of "banned" edges used by the deviation algorithm.
+// Any non-nil value stored for a pair of nodes bans all the edges between them,
+// except for an EdgeIDSet, which bans only the edges whose identifier it contains.
 type UnusableEdgeMap map[string]map[string]interface{}
 
+// EdgeIDSet is a set of edge identifiers
+type EdgeIDSet map[string]struct{}
+
 // EmptyUnnusableEdgeMap creates an empty UnusableEdgeMap
 func EmptyUnusableEdgeMap() UnusableEdgeMap {
 	return make(map[string]map[string]interface{})
 }
+
+// Ban bans all the edges going from node from to node to
+func (m UnusableEdgeMap) Ban(from, to string) {
+	if _, ok := m[from]; !ok {
+		m[from] = make(map[string]interface{})
+	}
+	m[from][to] = struct{}{}
+}
+
+// BanEdge bans the edge going from node from to node to identified by id.
+// An empty id bans all the edges between the two nodes.
+func (m UnusableEdgeMap) BanEdge(from, to, id string) {
+	if id == "" {
+		m.Ban(from, to)
+		return
+	}
+	if _, ok := m[from]; !ok {
+		m[from] = make(map[string]interface{})
+	}
+	switch v := m[from][to].(type) {
+	case nil:
+		m[from][to] = EdgeIDSet{id: struct{}{}}
+	case EdgeIDSet:
+		v[id] = struct{}{}
+	}
+}
+
+// IsBanned states if the edge going from node from to node to identified by id is banned
+func (m UnusableEdgeMap) IsBanned(from, to, id string) bool {
+	switch v := m[from][to].(type) {
+	case nil:
+		return false
+	case EdgeIDSet:
+		_, ok := v[id]
+		return ok && id != ""
+	default:
+		return true
+	}
+}
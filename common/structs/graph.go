@@ -26,3 +26,28 @@ type GraphObject interface {
 	PredecessorsFromNode(node string) []Connection // get predecessors for node
 	EdgeWeight(n1, n2 string) int                  // get edge weight
 }
+
+// TimeDependentGraph interface defines a graph whose edge weights are travel times depending on the time
+// the source node of the edge is reached, as used by time-dependent searches.
+// The travel time profiles of all edges should have the FIFO property.
+type TimeDependentGraph interface {
+	TimeDependentSuccessorsForNode(node string) []TimeDependentConnection // get successors for node
+}
+
+// ResourceGraph interface defines a graph whose edges consume one or more resources besides having a weight,
+// as used by resource-constrained searches. Both weights and resource consumptions must be non-negative.
+type ResourceGraph interface {
+	ResourceSuccessorsForNode(node string) []ResourceConnection // get successors for node
+}
+
+// MultiCriteriaGraph interface defines a graph whose edges have a weight for each optimization criterion,
+// as used by multi-objective searches. All the weights must be non-negative.
+type MultiCriteriaGraph interface {
+	MultiCriteriaSuccessorsForNode(node string) []MultiCriteriaConnection // get successors for node
+}
+
+// UnweightedGraph interface can be implemented by a graph object whose edges all have weight 1, such that searches
+// can count hops with a breadth-first search instead of running Dijkstra algorithm.
+type UnweightedGraph interface {
+	IsUnweighted() bool // true if every edge weight is 1
+}
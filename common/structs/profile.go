@@ -0,0 +1,69 @@
+/*
+Copyright 2013 Alessandro Frossi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dijkstrastructs
+
+// ProfilePoint is a breakpoint of a TravelTimeProfile
+type ProfilePoint struct {
+	Time     int // Departure time
+	Duration int // Travel time when departing at Time
+}
+
+// TravelTimeProfile is a piecewise-linear function giving the travel time along an edge for each departure time.
+// Breakpoints must be sorted by Time; the travel time is constant before the first and after the last breakpoint
+// and linearly interpolated in between. An empty profile has a travel time of 0.
+type TravelTimeProfile []ProfilePoint
+
+// ConstantProfile creates a TravelTimeProfile whose travel time is d for every departure time
+func ConstantProfile(d int) TravelTimeProfile {
+	return TravelTimeProfile{ProfilePoint{0, d}}
+}
+
+// TravelTime returns the travel time when departing at time t
+func (p TravelTimeProfile) TravelTime(t int) int {
+	if len(p) == 0 {
+		return 0
+	}
+	if t <= p[0].Time {
+		return p[0].Duration
+	}
+	for i := 1; i < len(p); i++ {
+		if t <= p[i].Time {
+			prev := p[i-1]
+			return prev.Duration + (p[i].Duration-prev.Duration)*(t-prev.Time)/(p[i].Time-prev.Time)
+		}
+	}
+	return p[len(p)-1].Duration
+}
+
+// ArrivalTime returns the arrival time when departing at time t
+func (p TravelTimeProfile) ArrivalTime(t int) int {
+	return t + p.TravelTime(t)
+}
+
+// IsFIFO states if the profile has the FIFO property, i.e. departing later never leads to an earlier arrival.
+// Time-dependent searches are only guaranteed to be correct on FIFO profiles.
+func (p TravelTimeProfile) IsFIFO() bool {
+	for i := 1; i < len(p); i++ {
+		if p[i].Time <= p[i-1].Time {
+			return false
+		}
+		if p[i].Duration-p[i-1].Duration < p[i-1].Time-p[i].Time {
+			return false
+		}
+	}
+	return true
+}
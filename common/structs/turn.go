@@ -0,0 +1,60 @@
+/*
+Copyright 2013 Alessandro Frossi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dijkstrastructs
+
+// TurnCostModel interface defines the cost of the turns of a graph, i.e. of moving from the edge going from node from
+// to node via onto the edge going from node via to node to.
+// TurnCost returns the non-negative cost of the turn and whether the turn is allowed at all.
+type TurnCostModel interface {
+	TurnCost(from, via, to string) (int, bool) // get turn cost
+}
+
+type turn struct {
+	from, via, to string
+}
+
+type turnEntry struct {
+	cost      int
+	forbidden bool
+}
+
+// TurnTable is a TurnCostModel listing forbidden turns and turn costs.
+// Turns that are not listed are allowed at no cost.
+type TurnTable struct {
+	turns map[turn]turnEntry
+}
+
+// NewTurnTable creates an empty TurnTable
+func NewTurnTable() *TurnTable {
+	return &TurnTable{make(map[turn]turnEntry)}
+}
+
+// Forbid forbids the turn from edge (from, via) onto edge (via, to)
+func (tt *TurnTable) Forbid(from, via, to string) {
+	tt.turns[turn{from, via, to}] = turnEntry{forbidden: true}
+}
+
+// SetCost sets the cost of the turn from edge (from, via) onto edge (via, to)
+func (tt *TurnTable) SetCost(from, via, to string, cost int) {
+	tt.turns[turn{from, via, to}] = turnEntry{cost: cost}
+}
+
+// TurnCost returns the cost of the turn from edge (from, via) onto edge (via, to) and whether it is allowed
+func (tt *TurnTable) TurnCost(from, via, to string) (int, bool) {
+	e := tt.turns[turn{from, via, to}]
+	return e.cost, !e.forbidden
+}
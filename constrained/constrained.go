@@ -0,0 +1,155 @@
+/*
+Copyright 2013 Alessandro Frossi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package Constrained implements a label-setting algorithm for the resource-constrained shortest path problem.
+//
+// The search works on graph objects implementing the dijkstrastructs.ResourceGraph interface, whose edges have a weight
+// and consume a set of resources. It returns the path of minimum weight whose overall consumption of each resource
+// does not exceed the given budget.
+// Since a node can be reached by several paths trading weight for resources, the search keeps a set of labels for each
+// node and discards a label only when another label at the same node is no worse in weight and in every resource.
+package constrained
+
+import (
+	"container/heap"
+	"github.com/kirves/godijkstra/common/path"
+	"github.com/kirves/godijkstra/common/structs"
+)
+
+// label is a partial path reaching a node, together with its resource consumption.
+type label struct {
+	candidate *dijkstrastructs.DijkstraCandidate // Last step of the partial path
+	resources []int                              // Resources consumed so far
+}
+
+// ShortestPath returns the path of minimum weight within the provided graph that goes from startNode to endNode
+// and consumes at most budgets[i] of the i-th resource; resources beyond len(budgets) are not constrained.
+// The resource consumption of the returned path is returned as well.
+func ShortestPath(graph dijkstrastructs.ResourceGraph, startNode, endNode string, budgets []int) (dijkstrapath.DijkstraPath, []int, bool) {
+	settled := make(map[string][]label)
+
+	openList := &labelQueue{}
+	heap.Init(openList)
+	heap.Push(openList, label{&dijkstrastructs.DijkstraCandidate{Node: startNode}, make([]int, len(budgets))})
+
+	for openList.Len() > 0 {
+		l := heap.Pop(openList).(label)
+		node := l.candidate.Node
+
+		if isDominated(l, settled[node]) {
+			continue
+		}
+		settled[node] = append(settled[node], l)
+
+		// check if we reached termination
+		if node == endNode {
+			cs := dijkstrastructs.CandidateSolution{
+				Length:        l.candidate.Weight,
+				ForwCandidate: l.candidate,
+				BackCandidate: &dijkstrastructs.DijkstraCandidate{Node: endNode},
+			}
+			return dijkstrapath.ConvertToDijkstraPath(cs, startNode, endNode), l.resources, true
+		}
+
+		for _, s := range graph.ResourceSuccessorsForNode(node) {
+			resources, feasible := consume(l.resources, s.Resources, budgets)
+			if !feasible {
+				continue
+			}
+			newLabel := label{
+				&dijkstrastructs.DijkstraCandidate{Node: s.Destination, Parent: l.candidate, Weight: l.candidate.Weight + s.Weight},
+				resources,
+			}
+			if isDominated(newLabel, settled[s.Destination]) {
+				continue
+			}
+			heap.Push(openList, newLabel)
+		}
+	}
+	return dijkstrapath.DijkstraPath{}, nil, false
+}
+
+// consume adds the consumption of an edge to the resources used so far and checks it against the budgets.
+func consume(used, edge, budgets []int) ([]int, bool) {
+	ret := make([]int, len(used))
+	for i := range used {
+		ret[i] = used[i]
+		if i < len(edge) {
+			ret[i] += edge[i]
+		}
+		if ret[i] > budgets[i] {
+			return nil, false
+		}
+	}
+	return ret, true
+}
+
+// isDominated states if one of labels is no worse than l in weight and in every resource.
+func isDominated(l label, labels []label) bool {
+	for _, o := range labels {
+		if dominates(o, l) {
+			return true
+		}
+	}
+	return false
+}
+
+func dominates(a, b label) bool {
+	if a.candidate.Weight > b.candidate.Weight {
+		return false
+	}
+	for i := range a.resources {
+		if a.resources[i] > b.resources[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// labelQueue is a heap of labels sorted by weight, breaking ties by resource consumption.
+type labelQueue []label
+
+func (pq labelQueue) Len() int {
+	return len(pq)
+}
+
+func (pq labelQueue) Less(i, j int) bool {
+	if pq[i].candidate.Weight != pq[j].candidate.Weight {
+		return pq[i].candidate.Weight < pq[j].candidate.Weight
+	}
+	for k := range pq[i].resources {
+		if pq[i].resources[k] != pq[j].resources[k] {
+			return pq[i].resources[k] < pq[j].resources[k]
+		}
+	}
+	return false
+}
+
+func (pq labelQueue) Swap(i, j int) {
+	pq[i], pq[j] = pq[j], pq[i]
+}
+
+func (pq *labelQueue) Push(x interface{}) {
+	*pq = append(*pq, x.(label))
+}
+
+func (pq *labelQueue) Pop() interface{} {
+	old := *pq
+	n := len(old)
+	x := old[n-1]
+	*pq = old[0 : n-1]
+	return x
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2013 Alessandro Frossi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package constrained
+
+import (
+	"testing"
+)
+
+var (
+	graph *testGraph
+)
+
+func init() {
+	// resources: toll, hops
+	graph = newTestGraph()
+	graph.addEdge("S", "A", 1, 10, 1)
+	graph.addEdge("A", "T", 1, 10, 1)
+	graph.addEdge("S", "B", 3, 2, 1)
+	graph.addEdge("B", "T", 3, 2, 1)
+	graph.addEdge("S", "C", 2, 0, 1)
+	graph.addEdge("C", "D", 2, 0, 1)
+	graph.addEdge("D", "E", 2, 0, 1)
+	graph.addEdge("E", "T", 2, 0, 1)
+}
+
+func TestConstrainedPath(t *testing.T) {
+	cases := []struct {
+		budgets      []int
+		expPath      []string
+		expWeight    int
+		expResources []int
+	}{
+		{[]int{100, 100}, []string{"S", "A", "T"}, 2, []int{20, 2}},
+		{[]int{10, 100}, []string{"S", "B", "T"}, 6, []int{4, 2}},
+		{[]int{0, 100}, []string{"S", "C", "D", "E", "T"}, 8, []int{0, 4}},
+		{[]int{4}, []string{"S", "B", "T"}, 6, []int{4}},
+	}
+	for _, c := range cases {
+		path, resources, valid := ShortestPath(graph, "S", "T", c.budgets)
+		if !valid {
+			t.Fatal("Validity error.")
+		}
+		if len(path.Path) != len(c.expPath) {
+			t.Fatalf("Wrong path: %v", path.Path)
+		}
+		for i, v := range path.Path {
+			if v.Node != c.expPath[i] {
+				t.Fatalf("Wrong path: %v", path.Path)
+			}
+		}
+		if path.Weight != c.expWeight {
+			t.Fatalf("Wrong path weight:\nExpected: %d\nGot: %d\n", c.expWeight, path.Weight)
+		}
+		for i := range c.expResources {
+			if resources[i] != c.expResources[i] {
+				t.Fatalf("Wrong resource consumption:\nExpected: %v\nGot: %v\n", c.expResources, resources)
+			}
+		}
+	}
+}
+
+func TestInfeasible(t *testing.T) {
+	if _, _, valid := ShortestPath(graph, "S", "T", []int{0, 3}); valid {
+		t.Fatal("A path was found exceeding the budget.")
+	}
+}
@@ -0,0 +1,37 @@
+/*
+Copyright 2013 Alessandro Frossi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package constrained
+
+import (
+	"github.com/kirves/godijkstra/common/structs"
+)
+
+type testGraph struct {
+	edges map[string][]dijkstrastructs.ResourceConnection
+}
+
+func newTestGraph() *testGraph {
+	return &testGraph{make(map[string][]dijkstrastructs.ResourceConnection)}
+}
+
+func (t *testGraph) addEdge(n1, n2 string, w int, resources ...int) {
+	t.edges[n1] = append(t.edges[n1], dijkstrastructs.ResourceConnection{Destination: n2, Weight: w, Resources: resources})
+}
+
+func (t *testGraph) ResourceSuccessorsForNode(node string) []dijkstrastructs.ResourceConnection {
+	return t.edges[node]
+}
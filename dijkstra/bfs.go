@@ -0,0 +1,156 @@
+/*
+Copyright 2013 Alessandro Frossi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dijkstra
+
+import (
+	"github.com/kirves/godijkstra/common/path"
+	"github.com/kirves/godijkstra/common/structs"
+)
+
+// BreadthFirstSearch returns the path within the provided graph object that goes from startNode to endNode nodes using the fewest edges,
+// found with a breadth-first search. Edge weights are ignored: the weight of the returned path is its number of edges.
+func BreadthFirstSearch(graph dijkstrastructs.GraphObject, startNode, endNode string, bannedEdges dijkstrastructs.UnusableEdgeMap) (dijkstrapath.DijkstraPath, bool) {
+	return newWorkspace(SearchOptions{}).bfs(graph, startNode, endNode, bannedEdges)
+}
+
+// BiDirBreadthFirstSearch returns the path within the provided graph object that goes from startNode to endNode nodes using the fewest edges,
+// found with a bidirectional breadth-first search. Edge weights are ignored: the weight of the returned path is its number of edges.
+func BiDirBreadthFirstSearch(graph dijkstrastructs.GraphObject, startNode, endNode string, bannedEdges dijkstrastructs.UnusableEdgeMap) (dijkstrapath.DijkstraPath, bool) {
+	return newWorkspace(SearchOptions{}).biDirBFS(graph, startNode, endNode, bannedEdges)
+}
+
+// isUnweighted states if graph declares all its edges to have weight 1 and opts do not change edge weights.
+func isUnweighted(graph dijkstrastructs.GraphObject, opts SearchOptions) bool {
+	ug, ok := graph.(dijkstrastructs.UnweightedGraph)
+	return ok && ug.IsUnweighted() && opts.Cost == nil && opts.TieBreak == TieBreakNone
+}
+
+// selectSearchType returns the breadth-first counterpart of searchType if graph is unweighted.
+func selectSearchType(graph dijkstrastructs.GraphObject, searchType int, opts SearchOptions) int {
+	if !isUnweighted(graph, opts) {
+		return searchType
+	}
+	switch searchType {
+	case VANILLA, DIAL:
+		return BFS
+	case BIDIR:
+		return BIDIR_BFS
+	}
+	return searchType
+}
+
+func (ws *workspace) bfs(graph dijkstrastructs.GraphObject, startNode, endNode string, bannedEdges dijkstrastructs.UnusableEdgeMap) (dijkstrapath.DijkstraPath, bool) {
+	graph = ws.opts.graph(graph)
+	visitedNodesF := ws.visitedNodesF
+	first := ws.newCandidate(startNode, nil, 0)
+	visitedNodesF[startNode] = first
+	frontier := append(ws.frontierF[:0], first)
+
+	var last *dijkstrastructs.DijkstraCandidate
+	for head := 0; head < len(frontier); head++ {
+		forwCandidate := frontier[head]
+		if forwCandidate.Node == endNode {
+			last = forwCandidate
+			break
+		}
+		ws.succs = appendSuccessors(ws.succs[:0], graph, forwCandidate, bannedEdges)
+		for _, s := range ws.succs {
+			if _, ok := visitedNodesF[s.Destination]; ok {
+				continue
+			}
+			newPath := ws.newCandidateFromConnection(forwCandidate, s, forwCandidate.Weight+1)
+			visitedNodesF[s.Destination] = newPath
+			frontier = append(frontier, newPath)
+		}
+	}
+	ws.frontierF = frontier
+
+	if last == nil || (ws.opts.MaxHops > 0 && last.Hops > ws.opts.MaxHops) {
+		return dijkstrapath.DijkstraPath{}, false
+	}
+	cs := dijkstrastructs.CandidateSolution{
+		Length:        last.Weight,
+		ForwCandidate: last,
+		BackCandidate: &dijkstrastructs.DijkstraCandidate{Node: endNode},
+	}
+	return dijkstrapath.ConvertToDijkstraPath(cs, startNode, endNode), true
+}
+
+// biDirBFS alternates the expansion of whole levels of the forward and backward searches, always expanding
+// the smaller frontier. The first level reaching a node visited by the other search contains a shortest path.
+func (ws *workspace) biDirBFS(graph dijkstrastructs.GraphObject, startNode, endNode string, bannedEdges dijkstrastructs.UnusableEdgeMap) (dijkstrapath.DijkstraPath, bool) {
+	graph = ws.opts.graph(graph)
+	visitedNodesB, _ := ws.backward()
+	visitedNodesF := ws.visitedNodesF
+	first := ws.newCandidate(startNode, nil, 0)
+	last := ws.newCandidate(endNode, nil, 0)
+	visitedNodesF[startNode] = first
+	visitedNodesB[endNode] = last
+	frontierF := append(ws.frontierF[:0], first)
+	frontierB := append(ws.frontierB[:0], last)
+
+	var cs dijkstrastructs.CandidateSolution
+	found := startNode == endNode
+	if found {
+		cs = dijkstrastructs.CandidateSolution{Length: 0, ForwCandidate: first, BackCandidate: last}
+	}
+	for !found && len(frontierF) > 0 && len(frontierB) > 0 {
+		forward := len(frontierF) <= len(frontierB)
+		frontier, visited, other := frontierB, visitedNodesB, visitedNodesF
+		if forward {
+			frontier, visited, other = frontierF, visitedNodesF, visitedNodesB
+		}
+		next := ws.next[:0]
+		for _, c := range frontier {
+			if forward {
+				ws.succs = appendSuccessors(ws.succs[:0], graph, c, bannedEdges)
+			} else {
+				ws.succs = appendPredecessors(ws.succs[:0], graph, c, bannedEdges)
+			}
+			for _, s := range ws.succs {
+				if _, ok := visited[s.Destination]; ok {
+					continue
+				}
+				newPath := ws.newCandidateFromConnection(c, s, c.Weight+1)
+				visited[s.Destination] = newPath
+				next = append(next, newPath)
+				if o, ok := other[s.Destination]; ok && (!found || newPath.Weight+o.Weight < cs.Length) {
+					found = true
+					cs.Length = newPath.Weight + o.Weight
+					if forward {
+						cs.ForwCandidate, cs.BackCandidate = newPath, o
+					} else {
+						cs.ForwCandidate, cs.BackCandidate = o, newPath
+					}
+				}
+			}
+		}
+		// swap the expanded frontier with the next level, keeping both buffers for later searches
+		ws.next = frontier
+		if forward {
+			frontierF = next
+		} else {
+			frontierB = next
+		}
+	}
+	ws.frontierF, ws.frontierB = frontierF, frontierB
+
+	if !found || (ws.opts.MaxHops > 0 && cs.Length > ws.opts.MaxHops) {
+		return dijkstrapath.DijkstraPath{}, false
+	}
+	return dijkstrapath.ConvertToDijkstraPath(cs, startNode, endNode), true
+}
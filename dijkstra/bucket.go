@@ -0,0 +1,112 @@
+/*
+Copyright 2013 Alessandro Frossi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dijkstra
+
+import (
+	"github.com/kirves/godijkstra/common/structs"
+)
+
+// bucketQueue is the bucket queue of Dial's algorithm: candidates are stored in a circular array of buckets,
+// one per weight, and extracted by scanning the buckets from the weight of the last extracted candidate.
+// Since Dijkstra's algorithm never queues a candidate lighter than the last extracted one and heavier than it
+// by more than the maximum edge weight, the array only needs as many buckets as the maximum edge weight plus one;
+// it starts small and doubles whenever a heavier candidate is pushed.
+type bucketQueue struct {
+	buckets [][]*dijkstrastructs.DijkstraCandidate
+	cur     int // Weight of the bucket being extracted
+	n       int // Number of queued candidates
+}
+
+func newBucketQueue() *bucketQueue {
+	return &bucketQueue{buckets: make([][]*dijkstrastructs.DijkstraCandidate, 16)}
+}
+
+func (q *bucketQueue) Len() int {
+	return q.n
+}
+
+func (q *bucketQueue) push(c *dijkstrastructs.DijkstraCandidate) {
+	if q.n == 0 || c.Weight < q.cur {
+		// a lighter candidate can only be pushed at the start of a search, or with negative weights
+		q.relayout(c.Weight, c.Weight)
+	} else if c.Weight-q.cur >= len(q.buckets) {
+		q.relayout(q.cur, c.Weight)
+	}
+	i := q.slot(c.Weight)
+	q.buckets[i] = append(q.buckets[i], c)
+	q.n++
+}
+
+func (q *bucketQueue) pop() *dijkstrastructs.DijkstraCandidate {
+	i := q.slot(q.cur)
+	for len(q.buckets[i]) == 0 {
+		q.cur++
+		i = q.slot(q.cur)
+	}
+	b := q.buckets[i]
+	c := b[len(b)-1]
+	q.buckets[i] = b[0 : len(b)-1]
+	q.n--
+	return c
+}
+
+func (q *bucketQueue) improves(node string, w int) bool {
+	return true
+}
+
+func (q *bucketQueue) reset() {
+	for i := range q.buckets {
+		q.buckets[i] = q.buckets[i][:0]
+	}
+	q.cur, q.n = 0, 0
+}
+
+func (q *bucketQueue) slot(w int) int {
+	i := w % len(q.buckets)
+	if i < 0 {
+		i += len(q.buckets)
+	}
+	return i
+}
+
+// relayout moves the extraction point to weight cur and redistributes the queued candidates,
+// doubling the number of buckets until weights up to max fit.
+func (q *bucketQueue) relayout(cur, max int) {
+	old := q.buckets
+	for _, b := range old {
+		for _, c := range b {
+			if c.Weight > max {
+				max = c.Weight
+			}
+		}
+	}
+	size := len(old)
+	for max-cur >= size {
+		size *= 2
+	}
+	q.cur = cur
+	if q.n == 0 && size == len(old) {
+		return
+	}
+	q.buckets = make([][]*dijkstrastructs.DijkstraCandidate, size)
+	for _, b := range old {
+		for _, c := range b {
+			i := q.slot(c.Weight)
+			q.buckets[i] = append(q.buckets[i], c)
+		}
+	}
+}
@@ -19,22 +19,38 @@ limitations under the License.
 // The Dijkstra Algorithm traverses a graph object implementing the dijkstrastruct.GraphObject interface to find the shortest path;
 // the only limitation is that all the edges' weights must be non-negative.
 // The returned path, an instance of DijkstraPath struct, is a loopless path going from the starting node to the destination;
-// it can be computed using either the "vanilla" Dijkstra algorithm or a bidirectional search algorithm.
+// it can be computed using the "vanilla" Dijkstra algorithm, a bidirectional search algorithm or Dial's algorithm.
+// Paths minimizing the number of edges can be found with a (bidirectional) breadth-first search.
 package dijkstra
 
 import (
-	"container/heap"
 	"github.com/kirves/godijkstra/common/path"
 	"github.com/kirves/godijkstra/common/structs"
 )
 
 const (
-	VANILLA = iota // Use "vanilla" Dijkstra algorithm
-	BIDIR          // Use bi-directional search algorithm
+	VANILLA      = iota // Use "vanilla" Dijkstra algorithm
+	BIDIR               // Use bi-directional search algorithm
+	DIAL                // Use Dial's algorithm, for small integer weights
+	BFS                 // Use breadth-first search, counting hops instead of weights
+	BIDIR_BFS           // Use bi-directional breadth-first search, counting hops instead of weights
+	ZERO_ONE_BFS        // Use 0-1 breadth-first search, for weights of 0 or 1; silently falls back to VANILLA on any other weight or with MaxHops
 )
 
 func newDijkstraCandidate(node string, parent *dijkstrastructs.DijkstraCandidate, w int) *dijkstrastructs.DijkstraCandidate {
-	return &dijkstrastructs.DijkstraCandidate{node, parent, w}
+	hops := 0
+	if parent != nil {
+		hops = parent.Hops + 1
+	}
+	return &dijkstrastructs.DijkstraCandidate{Node: node, Parent: parent, Weight: w, Hops: hops}
+}
+
+// newDijkstraCandidateFromConnection creates the candidate reached from parent through the edge c.
+func newDijkstraCandidateFromConnection(parent *dijkstrastructs.DijkstraCandidate, c dijkstrastructs.Connection, w int) *dijkstrastructs.DijkstraCandidate {
+	dc := newDijkstraCandidate(c.Destination, parent, w)
+	dc.EdgeID = c.ID
+	dc.EdgeAttributes = c.Attributes
+	return dc
 }
 
 // func (cs CandidateSolution) IsEqualTo(sol CandidateSolution) bool {
@@ -47,36 +63,108 @@ func newDijkstraCandidate(node string, parent *dijkstrastructs.DijkstraCandidate
 // Dijkstra returns the shortest path within the provided graph object that goes from startNode to endNode nodes.
 // searchType parameter defines the type of algorithm to use.
 func SearchPath(graph dijkstrastructs.GraphObject, startNode, endNode string, searchType int) (dijkstrapath.DijkstraPath, bool) {
-	switch searchType {
-	case VANILLA:
-		return Dijkstra(graph, startNode, endNode, dijkstrastructs.EmptyUnusableEdgeMap())
-	case BIDIR:
-		return BiDirDijkstra(graph, startNode, endNode, dijkstrastructs.EmptyUnusableEdgeMap())
-	default:
+	return SearchPathWithOptions(graph, startNode, endNode, searchType, SearchOptions{})
+}
+
+// SearchPathWithOptions returns the shortest path within the provided graph object that goes from startNode to endNode nodes,
+// satisfying the constraints defined by opts. searchType parameter defines the type of algorithm to use.
+func SearchPathWithOptions(graph dijkstrastructs.GraphObject, startNode, endNode string, searchType int, opts SearchOptions) (dijkstrapath.DijkstraPath, bool) {
+	return SearchFunc(searchType, opts)(graph, startNode, endNode, dijkstrastructs.EmptyUnusableEdgeMap())
+}
+
+// SearchFunc returns a search function running the algorithm defined by searchType with the options opts,
+// to be used where a search function is expected (e.g. by Yen's algorithm).
+// If the searched graph implements dijkstrastructs.UnweightedGraph and declares itself unweighted, VANILLA and DIAL searches
+// run BFS and BIDIR searches run BIDIR_BFS, unless opts define a cost function or a tie-breaking mode.
+func SearchFunc(searchType int, opts SearchOptions) func(dijkstrastructs.GraphObject, string, string, dijkstrastructs.UnusableEdgeMap) (dijkstrapath.DijkstraPath, bool) {
+	return func(graph dijkstrastructs.GraphObject, startNode, endNode string, bannedEdges dijkstrastructs.UnusableEdgeMap) (dijkstrapath.DijkstraPath, bool) {
+		switch selectSearchType(graph, searchType, opts) {
+		case VANILLA:
+			return DijkstraWithOptions(graph, startNode, endNode, bannedEdges, opts)
+		case BIDIR:
+			return BiDirDijkstraWithOptions(graph, startNode, endNode, bannedEdges, opts)
+		case DIAL:
+			opts.Queue = BucketQueue
+			return DijkstraWithOptions(graph, startNode, endNode, bannedEdges, opts)
+		case BFS:
+			return newWorkspace(opts).bfs(graph, startNode, endNode, bannedEdges)
+		case BIDIR_BFS:
+			return newWorkspace(opts).biDirBFS(graph, startNode, endNode, bannedEdges)
+		case ZERO_ONE_BFS:
+			return newWorkspace(opts).zeroOneBFS(graph, startNode, endNode, bannedEdges)
+		}
 		return dijkstrapath.DijkstraPath{}, false
 	}
 }
 
 func Dijkstra(graph dijkstrastructs.GraphObject, startNode, endNode string, bannedEdges dijkstrastructs.UnusableEdgeMap) (dijkstrapath.DijkstraPath, bool) {
+	return DijkstraWithOptions(graph, startNode, endNode, bannedEdges, SearchOptions{})
+}
+
+// DijkstraWithOptions returns the shortest path within the provided graph object that goes from startNode to endNode nodes,
+// satisfying the constraints defined by opts.
+func DijkstraWithOptions(graph dijkstrastructs.GraphObject, startNode, endNode string, bannedEdges dijkstrastructs.UnusableEdgeMap, opts SearchOptions) (dijkstrapath.DijkstraPath, bool) {
+	return newWorkspace(opts).dijkstra(graph, startNode, endNode, bannedEdges)
+}
+
+func (ws *workspace) dijkstra(graph dijkstrastructs.GraphObject, startNode, endNode string, bannedEdges dijkstrastructs.UnusableEdgeMap) (dijkstrapath.DijkstraPath, bool) {
+	graph = ws.opts.graph(graph)
 	// SETUP ================================
-	firstParent := newDijkstraCandidate(startNode, nil, 0)
+	firstParent := ws.newCandidate(startNode, nil, 0)
 	startSet := []*dijkstrastructs.DijkstraCandidate{firstParent}
 	// ======================================
-	cs, valid := computeVanillaDijkstra(graph, startSet, endNode, bannedEdges)
+	var cs dijkstrastructs.CandidateSolution
+	var valid bool
+	if ws.opts.MaxHops > 0 {
+		cs, valid = computeHopLimitedDijkstra(graph, startSet, endNode, bannedEdges, ws.opts.MaxHops, ws)
+	} else {
+		cs, valid = computeVanillaDijkstra(graph, startSet, endNode, bannedEdges, ws)
+	}
 	if !valid {
 		return dijkstrapath.DijkstraPath{}, false
 	}
 	return dijkstrapath.ConvertToDijkstraPath(cs, startNode, endNode), true
 }
 
+// DialDijkstra returns the shortest path within the provided graph object that goes from startNode to endNode nodes
+// using Dial's algorithm, where candidates are kept in buckets indexed by weight instead of a heap.
+// It is faster than Dijkstra when edge weights are small integers, as it takes O(E + W·V) time with W the maximum edge weight.
+func DialDijkstra(graph dijkstrastructs.GraphObject, startNode, endNode string, bannedEdges dijkstrastructs.UnusableEdgeMap) (dijkstrapath.DijkstraPath, bool) {
+	return DijkstraWithOptions(graph, startNode, endNode, bannedEdges, SearchOptions{Queue: BucketQueue})
+}
+
+// HopLimitedDijkstra returns the shortest path within the provided graph object that goes from startNode to endNode nodes
+// using at most maxHops edges. A non-positive maxHops means no limit.
+func HopLimitedDijkstra(graph dijkstrastructs.GraphObject, startNode, endNode string, bannedEdges dijkstrastructs.UnusableEdgeMap, maxHops int) (dijkstrapath.DijkstraPath, bool) {
+	return DijkstraWithOptions(graph, startNode, endNode, bannedEdges, SearchOptions{MaxHops: maxHops})
+}
+
 func BiDirDijkstra(graph dijkstrastructs.GraphObject, startNode, endNode string, bannedEdges dijkstrastructs.UnusableEdgeMap) (dijkstrapath.DijkstraPath, bool) {
+	return BiDirDijkstraWithOptions(graph, startNode, endNode, bannedEdges, SearchOptions{})
+}
+
+// BiDirDijkstraWithOptions returns the shortest path within the provided graph object that goes from startNode to endNode nodes,
+// satisfying the constraints defined by opts, using the bidirectional search algorithm.
+// The bidirectional search does not support a hop limit: if opts.MaxHops is set the vanilla algorithm is used instead.
+func BiDirDijkstraWithOptions(graph dijkstrastructs.GraphObject, startNode, endNode string, bannedEdges dijkstrastructs.UnusableEdgeMap, opts SearchOptions) (dijkstrapath.DijkstraPath, bool) {
+	if opts.MaxHops > 0 {
+		return DijkstraWithOptions(graph, startNode, endNode, bannedEdges, opts)
+	}
+	return newWorkspace(opts).biDirDijkstra(graph, startNode, endNode, bannedEdges)
+}
+
+func (ws *workspace) biDirDijkstra(graph dijkstrastructs.GraphObject, startNode, endNode string, bannedEdges dijkstrastructs.UnusableEdgeMap) (dijkstrapath.DijkstraPath, bool) {
+	if ws.opts.MaxHops > 0 {
+		return ws.dijkstra(graph, startNode, endNode, bannedEdges)
+	}
+	graph = ws.opts.graph(graph)
 	// SETUP ================================
-	firstParent := newDijkstraCandidate(startNode, nil, 0)
-	lastParent := newDijkstraCandidate(endNode, nil, 0)
+	firstParent := ws.newCandidate(startNode, nil, 0)
+	lastParent := ws.newCandidate(endNode, nil, 0)
 	startSet := []*dijkstrastructs.DijkstraCandidate{firstParent}
 	endSet := []*dijkstrastructs.DijkstraCandidate{lastParent}
 	// ======================================
-	cs, valid := computeBiDirDijkstra(graph, startSet, endSet, dijkstrastructs.EmptyUnusableEdgeMap())
+	cs, valid := computeBiDirDijkstra(graph, startSet, endSet, bannedEdges, ws)
 	if !valid {
 		return dijkstrapath.DijkstraPath{}, false
 	}
@@ -87,28 +175,25 @@ func computeVanillaDijkstra(
 	graph dijkstrastructs.GraphObject,
 	startSet []*dijkstrastructs.DijkstraCandidate,
 	endNode string,
-	bannedEdges dijkstrastructs.UnusableEdgeMap) (dijkstrastructs.CandidateSolution, bool) {
+	bannedEdges dijkstrastructs.UnusableEdgeMap,
+	ws *workspace) (dijkstrastructs.CandidateSolution, bool) {
 
 	candidateSolution := dijkstrastructs.CandidateSolution{0, nil, nil}
-	var succs []dijkstrastructs.Connection
-	visitedNodesF := make(map[string]*dijkstrastructs.DijkstraCandidate)
-
-	openListF := &DijkstraQueue{}
-	heap.Init(openListF)
+	visitedNodesF, openListF := ws.visitedNodesF, ws.openListF
 
 	// create initial path set
 	for _, c := range startSet {
-		heap.Push(openListF, c)
+		openListF.push(c)
 	}
 
 	for openListF.Len() > 0 {
 
 		// get candidates
-		forwCandidate := heap.Pop(openListF).(*dijkstrastructs.DijkstraCandidate)
+		forwCandidate := openListF.pop()
 
 		// check if we reached termination
 		if forwCandidate.Node == endNode {
-			return dijkstrastructs.CandidateSolution{forwCandidate.Weight, forwCandidate, &dijkstrastructs.DijkstraCandidate{endNode, nil, 0}}, true
+			return dijkstrastructs.CandidateSolution{forwCandidate.Weight, forwCandidate, &dijkstrastructs.DijkstraCandidate{Node: endNode}}, true
 		}
 
 		if _, ok := visitedNodesF[forwCandidate.Node]; ok {
@@ -117,149 +202,225 @@ func computeVanillaDijkstra(
 			visitedNodesF[forwCandidate.Node] = forwCandidate
 		}
 
-		succs = successorsForPath(graph, forwCandidate, bannedEdges)
+		ws.succs = appendSuccessors(ws.succs[:0], graph, forwCandidate, bannedEdges)
 
 		// for each successors
-		for _, s := range succs {
-			if _, ok := visitedNodesF[s.Destination]; ok {
+		for _, s := range ws.succs {
+			if _, ok := visitedNodesF[s.Destination]; ok || !openListF.improves(s.Destination, forwCandidate.Weight+s.Weight) {
 				continue
 			}
-			newPath := newDijkstraCandidate(s.Destination, forwCandidate, forwCandidate.Weight+s.Weight)
+			newPath := ws.newCandidateFromConnection(forwCandidate, s, forwCandidate.Weight+s.Weight)
 			// duplicate and add step
-			heap.Push(openListF, newPath)
+			openListF.push(newPath)
 		}
 	}
 	return candidateSolution, false
 }
 
-func computeBiDirDijkstra(
+// computeHopLimitedDijkstra runs Dijkstra algorithm over (node, hops) states.
+// Since candidates are extracted by increasing weight, a candidate is useful only if its node
+// has not been settled yet with fewer hops.
+func computeHopLimitedDijkstra(
 	graph dijkstrastructs.GraphObject,
 	startSet []*dijkstrastructs.DijkstraCandidate,
-	endSet []*dijkstrastructs.DijkstraCandidate,
-	bannedEdges dijkstrastructs.UnusableEdgeMap) (dijkstrastructs.CandidateSolution, bool) {
+	endNode string,
+	bannedEdges dijkstrastructs.UnusableEdgeMap,
+	maxHops int,
+	ws *workspace) (dijkstrastructs.CandidateSolution, bool) {
 
-	candidateSolution := dijkstrastructs.CandidateSolution{0, nil, nil}
-	skipForward := false
-	var succs []dijkstrastructs.Connection
-	visitedNodesF := make(map[string]*dijkstrastructs.DijkstraCandidate)
-	visitedNodesB := make(map[string]*dijkstrastructs.DijkstraCandidate)
+	openListF := ws.hopList()
+	minHops := ws.minHops
+
+	// create initial path set
+	for _, c := range startSet {
+		openListF.push(c)
+	}
 
-	openListF := &DijkstraQueue{}
-	openListB := &DijkstraQueue{}
-	heap.Init(openListF)
-	heap.Init(openListB)
+	for openListF.Len() > 0 {
+
+		// get candidates
+		forwCandidate := openListF.pop()
+
+		// check if we reached termination
+		if forwCandidate.Node == endNode {
+			return dijkstrastructs.CandidateSolution{
+				Length:        forwCandidate.Weight,
+				ForwCandidate: forwCandidate,
+				BackCandidate: &dijkstrastructs.DijkstraCandidate{Node: endNode},
+			}, true
+		}
+
+		if h, ok := minHops[forwCandidate.Node]; ok && h <= forwCandidate.Hops {
+			continue
+		}
+		minHops[forwCandidate.Node] = forwCandidate.Hops
+
+		if forwCandidate.Hops >= maxHops {
+			continue
+		}
+
+		ws.succs = appendSuccessors(ws.succs[:0], graph, forwCandidate, bannedEdges)
+
+		// for each successors
+		for _, s := range ws.succs {
+			if h, ok := minHops[s.Destination]; ok && h <= forwCandidate.Hops+1 {
+				continue
+			}
+			newPath := ws.newCandidateFromConnection(forwCandidate, s, forwCandidate.Weight+s.Weight)
+			openListF.push(newPath)
+		}
+	}
+	return dijkstrastructs.CandidateSolution{}, false
+}
+
+// computeBiDirDijkstra alternates a forward and a backward search, keeping the lightest path joining the two searches
+// found so far. Every time an edge links a node settled by one search to a node settled by the other, either when
+// a node is settled or when its edges are relaxed, the path going through it is considered; the search stops
+// as soon as the weights of the two next candidates sum up to at least the weight of the best path.
+func computeBiDirDijkstra(
+	graph dijkstrastructs.GraphObject,
+	startSet []*dijkstrastructs.DijkstraCandidate,
+	endSet []*dijkstrastructs.DijkstraCandidate,
+	bannedEdges dijkstrastructs.UnusableEdgeMap,
+	ws *workspace) (dijkstrastructs.CandidateSolution, bool) {
+
+	candidateSolution := dijkstrastructs.CandidateSolution{}
+	found := false
+	visitedNodesF, openListF := ws.visitedNodesF, ws.openListF
+	visitedNodesB, openListB := ws.backward()
+
+	// meet records the path made of the forward path f and the backward path b, ending and starting with the same node
+	meet := func(f, b *dijkstrastructs.DijkstraCandidate) {
+		if !found || f.Weight+b.Weight < candidateSolution.Length {
+			found = true
+			candidateSolution = dijkstrastructs.CandidateSolution{Length: f.Weight + b.Weight, ForwCandidate: f, BackCandidate: b}
+		}
+	}
 
 	// create initial path set
 	for _, c := range startSet {
-		heap.Push(openListF, c)
+		openListF.push(c)
 	}
 
 	for _, c := range endSet {
-		heap.Push(openListB, c)
+		openListB.push(c)
 	}
 
 	for openListF.Len() > 0 && openListB.Len() > 0 {
 
 		// get candidates
-		forwCandidate := heap.Pop(openListF).(*dijkstrastructs.DijkstraCandidate)
-		backCandidate := heap.Pop(openListB).(*dijkstrastructs.DijkstraCandidate)
+		forwCandidate := openListF.pop()
+		backCandidate := openListB.pop()
 
 		// check if we reached termination
-		if candidateSolution.Length != 0 && forwCandidate.Weight+backCandidate.Weight >= candidateSolution.Length {
+		if found && forwCandidate.Weight+backCandidate.Weight >= candidateSolution.Length {
 			break
 		}
 
 		// ***************************************************
 		// forward search
-		if _, ok := visitedNodesF[forwCandidate.Node]; ok {
-			skipForward = true
-		} else {
+		if _, ok := visitedNodesF[forwCandidate.Node]; !ok {
 			visitedNodesF[forwCandidate.Node] = forwCandidate
-			skipForward = false
-		}
 
-		if !skipForward {
 			if v, ok := visitedNodesB[forwCandidate.Node]; ok {
 				// found an explored backward path
-				newWeight := forwCandidate.Weight + v.Weight
-				if candidateSolution.Length == 0 || candidateSolution.Length > newWeight {
-					// found new solution candidate
-					candidateSolution.Length = newWeight
-					candidateSolution.ForwCandidate = forwCandidate
-					candidateSolution.BackCandidate = visitedNodesB[forwCandidate.Node]
-				}
+				meet(forwCandidate, v)
 			}
 
-			succs = successorsForPath(graph, forwCandidate, bannedEdges)
+			ws.succs = appendSuccessors(ws.succs[:0], graph, forwCandidate, bannedEdges)
 
 			// for each successors
-			for _, s := range succs {
+			for _, s := range ws.succs {
 				if _, ok := visitedNodesF[s.Destination]; ok {
 					continue
 				}
-				newPath := newDijkstraCandidate(s.Destination, forwCandidate, forwCandidate.Weight+s.Weight)
+				w := forwCandidate.Weight + s.Weight
+				v, met := visitedNodesB[s.Destination]
+				if met && (!found || w+v.Weight < candidateSolution.Length) {
+					// the edge reaches an explored backward path
+					meet(ws.newCandidateFromConnection(forwCandidate, s, w), v)
+				}
+				if !openListF.improves(s.Destination, w) {
+					continue
+				}
+				newPath := ws.newCandidateFromConnection(forwCandidate, s, w)
 				// duplicate and add step
-				heap.Push(openListF, newPath)
+				openListF.push(newPath)
 			}
 		}
 		// ****************************************************
 
 		// ***************************************************
 		// backward search
-		if _, ok := visitedNodesB[backCandidate.Node]; ok {
+		if !ws.settleBackward(graph, backCandidate, bannedEdges) {
 			continue
-		} else {
-			visitedNodesB[backCandidate.Node] = backCandidate
 		}
 
 		if v, ok := visitedNodesF[backCandidate.Node]; ok {
-			// found an explored backward path
-			newWeight := backCandidate.Weight + v.Weight
-			if candidateSolution.Length == 0 || candidateSolution.Length > newWeight {
-				// found new solution candidate
-				candidateSolution.Length = newWeight
-				candidateSolution.ForwCandidate = visitedNodesF[backCandidate.Node]
-				candidateSolution.BackCandidate = backCandidate
-			}
+			// found an explored forward path
+			meet(v, backCandidate)
 		}
 
-		succs = predecessorsForPath(graph, backCandidate, bannedEdges)
-
-		// for each successors
-		for _, s := range succs {
-			if _, ok := visitedNodesB[s.Destination]; ok {
-				continue
+		// settleBackward leaves the relaxed predecessors in ws.succs
+		for _, s := range ws.succs {
+			w := backCandidate.Weight + s.Weight
+			if v, ok := visitedNodesF[s.Destination]; ok && (!found || v.Weight+w < candidateSolution.Length) {
+				// the edge comes from an explored forward path
+				meet(v, ws.newCandidateFromConnection(backCandidate, s, w))
 			}
-			newPath := newDijkstraCandidate(s.Destination, backCandidate, backCandidate.Weight+s.Weight)
-			heap.Push(openListB, newPath)
 		}
 		// ****************************************************
 	}
 
-	if candidateSolution.Length == 0 {
-		return dijkstrastructs.CandidateSolution{}, false
+	return candidateSolution, found
+}
+
+// settleBackward marks backCandidate as visited by a backward search and pushes the paths reaching it from its
+// predecessors into the backward open list. It returns false if the node had already been visited.
+func (ws *workspace) settleBackward(
+	graph dijkstrastructs.GraphObject,
+	backCandidate *dijkstrastructs.DijkstraCandidate,
+	bannedEdges dijkstrastructs.UnusableEdgeMap) bool {
+
+	visitedNodesB, openListB := ws.backward()
+	if _, ok := visitedNodesB[backCandidate.Node]; ok {
+		return false
+	}
+	visitedNodesB[backCandidate.Node] = backCandidate
+
+	ws.succs = appendPredecessors(ws.succs[:0], graph, backCandidate, bannedEdges)
+
+	// for each predecessors
+	for _, s := range ws.succs {
+		if _, ok := visitedNodesB[s.Destination]; ok || !openListB.improves(s.Destination, backCandidate.Weight+s.Weight) {
+			continue
+		}
+		newPath := ws.newCandidateFromConnection(backCandidate, s, backCandidate.Weight+s.Weight)
+		openListB.push(newPath)
 	}
-	return candidateSolution, true
+	return true
 }
 
 func successorsForPath(graph dijkstrastructs.GraphObject, path *dijkstrastructs.DijkstraCandidate, bannedEdges dijkstrastructs.UnusableEdgeMap) []dijkstrastructs.Connection {
-	tmp := graph.SuccessorsForNode(path.Node)
-	ret := make([]dijkstrastructs.Connection, 0)
-	for _, s := range tmp {
-		if bannedEdges[path.Node][s.Destination] == nil {
-			ret = append(ret, s)
+	return appendSuccessors(make([]dijkstrastructs.Connection, 0), graph, path, bannedEdges)
+}
+
+// appendSuccessors appends to dst the edges leaving the last node of path that are not banned.
+func appendSuccessors(dst []dijkstrastructs.Connection, graph dijkstrastructs.GraphObject, path *dijkstrastructs.DijkstraCandidate, bannedEdges dijkstrastructs.UnusableEdgeMap) []dijkstrastructs.Connection {
+	for _, s := range graph.SuccessorsForNode(path.Node) {
+		if !bannedEdges.IsBanned(path.Node, s.Destination, s.ID) {
+			dst = append(dst, s)
 		}
 	}
-	return ret
+	return dst
 }
 
-func predecessorsForPath(graph dijkstrastructs.GraphObject, path *dijkstrastructs.DijkstraCandidate, bannedEdges dijkstrastructs.UnusableEdgeMap) []dijkstrastructs.Connection {
-	tmp := graph.PredecessorsFromNode(path.Node)
-	ret := make([]dijkstrastructs.Connection, 0)
-	for _, s := range tmp {
-		if bannedEdges[s.Destination][path.Node] == nil {
-			ret = append(ret, s)
+// appendPredecessors appends to dst the edges reaching the last node of path that are not banned.
+func appendPredecessors(dst []dijkstrastructs.Connection, graph dijkstrastructs.GraphObject, path *dijkstrastructs.DijkstraCandidate, bannedEdges dijkstrastructs.UnusableEdgeMap) []dijkstrastructs.Connection {
+	for _, s := range graph.PredecessorsFromNode(path.Node) {
+		if !bannedEdges.IsBanned(s.Destination, path.Node, s.ID) {
+			dst = append(dst, s)
 		}
 	}
-	return ret
+	return dst
 }
@@ -17,6 +17,11 @@ limitations under the License.
 package dijkstra
 
 import (
+	"github.com/kirves/godijkstra/common/path"
+	"github.com/kirves/godijkstra/common/structs"
+	"github.com/kirves/godijkstra/graphtest"
+	"math/rand"
+	"runtime"
 	"testing"
 )
 
@@ -151,3 +156,762 @@ func TestEquality(t *testing.T) {
 		t.Fatal("The algorithms yield different paths.")
 	}
 }
+
+func TestHopLimited(t *testing.T) {
+	g := newTestGraph()
+	g.addEdge("S", "A", 1)
+	g.addEdge("A", "B", 1)
+	g.addEdge("B", "C", 1)
+	g.addEdge("C", "T", 1)
+	g.addEdge("S", "B", 5)
+	g.addEdge("S", "T", 10)
+
+	cases := []struct {
+		maxHops int
+		expPath []string
+		weight  int
+	}{
+		{0, []string{"S", "A", "B", "C", "T"}, 4},
+		{4, []string{"S", "A", "B", "C", "T"}, 4},
+		{3, []string{"S", "B", "C", "T"}, 7},
+		{1, []string{"S", "T"}, 10},
+	}
+	for _, c := range cases {
+		path, valid := HopLimitedDijkstra(g, "S", "T", dijkstrastructs.EmptyUnusableEdgeMap(), c.maxHops)
+		if !valid {
+			t.Fatal("Validity error.")
+		}
+		if len(path.Path) != len(c.expPath) {
+			t.Fatalf("Wrong path with %d hops: %v", c.maxHops, path.Path)
+		}
+		for i, v := range path.Path {
+			if v.Node != c.expPath[i] {
+				t.Fatalf("Wrong path with %d hops: %v", c.maxHops, path.Path)
+			}
+		}
+		if path.Weight != c.weight {
+			t.Fatalf("Wrong path weight:\nExpected: %d\nGot: %d\n", c.weight, path.Weight)
+		}
+	}
+
+	delete(g.edges["S"], "T")
+	delete(g.reverseEdges["T"], "S")
+	if _, valid := HopLimitedDijkstra(g, "S", "T", dijkstrastructs.EmptyUnusableEdgeMap(), 2); valid {
+		t.Fatal("A path was found exceeding the hop limit.")
+	}
+}
+
+func TestRouteVia(t *testing.T) {
+	g := newTestGraph()
+	g.addEdge("S", "A", 1)
+	g.addEdge("A", "B", 1)
+	g.addEdge("A", "C", 1)
+	g.addEdge("B", "D", 1)
+	g.addEdge("D", "C", 1)
+	g.addEdge("C", "G", 1)
+	g.addEdge("G", "T", 1)
+	g.nodes["U"] = struct{}{}
+
+	waypoints := []string{"S", "B", "D", "T"}
+	for _, parallel := range []bool{false, true} {
+		route, valid := RouteVia(g, waypoints, RouteOptions{VANILLA, parallel})
+		if !valid {
+			t.Fatal("Validity error.")
+		}
+		expPath := []string{"S", "A", "B", "D", "C", "G", "T"}
+		if len(route.Path.Path) != len(expPath) {
+			t.Fatalf("Wrong path: %v", route.Path.Path)
+		}
+		for i, v := range route.Path.Path {
+			if v.Node != expPath[i] || v.Weight != i {
+				t.Fatalf("Wrong path: %v", route.Path.Path)
+			}
+		}
+		if route.Path.Weight != len(expPath)-1 {
+			t.Fatalf("Wrong path weight:\nExpected: %d\nGot: %d\n", len(expPath)-1, route.Path.Weight)
+		}
+		expLegs := []int{2, 3, 6}
+		for i, v := range route.LegEnds {
+			if v != expLegs[i] || route.Path.Path[v].Node != waypoints[i+1] {
+				t.Fatalf("Wrong leg boundaries: %v", route.LegEnds)
+			}
+		}
+	}
+
+	if _, valid := RouteVia(g, []string{"S", "B", "U"}, RouteOptions{}); valid {
+		t.Fatal("A route was found through an unreachable waypoint.")
+	}
+}
+
+func TestReachable(t *testing.T) {
+	g := newTestGraph()
+	g.addEdge("S", "A", 2)
+	g.addEdge("S", "B", 4)
+	g.addEdge("A", "B", 1)
+	g.addEdge("A", "C", 5)
+	g.addEdge("B", "C", 3)
+	g.addEdge("B", "D", 2)
+	g.addEdge("D", "E", 1)
+
+	r := Reachable(g, "S", 5, dijkstrastructs.EmptyUnusableEdgeMap())
+	expNodes := map[string]ReachableNode{
+		"S": {0, ""},
+		"A": {2, "S"},
+		"B": {3, "A"},
+		"D": {5, "B"},
+	}
+	if len(r.Nodes) != len(expNodes) {
+		t.Fatalf("Wrong reachable set: %v", r.Nodes)
+	}
+	for n, exp := range expNodes {
+		if r.Nodes[n] != exp {
+			t.Fatalf("Wrong reachable node %s:\nExpected: %v\nGot: %v\n", n, exp, r.Nodes[n])
+		}
+	}
+	expBoundary := map[string]BoundaryEdge{
+		"A-C": {"A", "C", 2, 5},
+		"B-C": {"B", "C", 3, 3},
+		"D-E": {"D", "E", 5, 1},
+	}
+	if len(r.Boundary) != len(expBoundary) {
+		t.Fatalf("Wrong boundary: %v", r.Boundary)
+	}
+	for _, e := range r.Boundary {
+		if expBoundary[e.From+"-"+e.To] != e {
+			t.Fatalf("Wrong boundary edge: %v", e)
+		}
+	}
+
+	banned := dijkstrastructs.EmptyUnusableEdgeMap()
+	banned["A"] = map[string]interface{}{"B": struct{}{}}
+	r = Reachable(g, "S", 5, banned)
+	if r.Nodes["B"].Weight != 4 || r.Nodes["B"].Parent != "S" {
+		t.Fatalf("Banned edge used: %v", r.Nodes)
+	}
+}
+
+func TestShortestPathsTo(t *testing.T) {
+	g := newTestGraph()
+	g.addEdge("S", "A", 2)
+	g.addEdge("S", "B", 4)
+	g.addEdge("A", "B", 1)
+	g.addEdge("A", "T", 9)
+	g.addEdge("B", "T", 3)
+	g.addEdge("T", "C", 1)
+
+	rt := ShortestPathsTo(g, "T", dijkstrastructs.EmptyUnusableEdgeMap())
+	expEntries := map[string]RoutingEntry{
+		"T": {Weight: 0},
+		"B": {Weight: 3, NextHop: "T"},
+		"A": {Weight: 4, NextHop: "B"},
+		"S": {Weight: 6, NextHop: "A"},
+	}
+	if len(rt.Entries) != len(expEntries) {
+		t.Fatalf("Wrong routing table: %v", rt.Entries)
+	}
+	for n, exp := range expEntries {
+		if rt.Entries[n] != exp {
+			t.Fatalf("Wrong routing entry for %s:\nExpected: %v\nGot: %v\n", n, exp, rt.Entries[n])
+		}
+		path, valid := rt.PathFrom(n)
+		expPath, _ := SearchPath(g, n, "T", VANILLA)
+		if !valid || !path.IsEqual(expPath) || path.Weight != expPath.Weight {
+			t.Fatalf("Wrong path from %s: %v", n, path.Path)
+		}
+		for i, v := range path.Path {
+			if v.Weight != expPath.Path[i].Weight {
+				t.Fatalf("Wrong path weights from %s: %v", n, path.Path)
+			}
+		}
+	}
+	if _, valid := rt.PathFrom("C"); valid {
+		t.Fatal("A path was found from a node not reaching the target.")
+	}
+}
+
+func TestTurnRestrictions(t *testing.T) {
+	// a two-way block: S -> A -> B -> T is the shortest route, but the turn A -> B -> T is forbidden
+	//   S - A - B - T
+	//       |   |
+	//       C - D
+	g := newTestGraph()
+	for _, e := range [][]string{{"S", "A"}, {"A", "B"}, {"B", "T"}, {"A", "C"}, {"C", "D"}, {"D", "B"}} {
+		g.addEdge(e[0], e[1], 1)
+		g.addEdge(e[1], e[0], 1)
+	}
+	turns := dijkstrastructs.NewTurnTable()
+
+	check := func(expPath []string, weight int) {
+		path, valid := TurnAwareDijkstra(g, turns, "S", "T", dijkstrastructs.EmptyUnusableEdgeMap())
+		if !valid {
+			t.Fatal("Validity error.")
+		}
+		if len(path.Path) != len(expPath) {
+			t.Fatalf("Wrong path: %v", path.Path)
+		}
+		for i, v := range path.Path {
+			if v.Node != expPath[i] {
+				t.Fatalf("Wrong path: %v", path.Path)
+			}
+		}
+		if path.Weight != weight {
+			t.Fatalf("Wrong path weight:\nExpected: %d\nGot: %d\n", weight, path.Weight)
+		}
+	}
+
+	check([]string{"S", "A", "B", "T"}, 3)
+
+	turns.SetCost("A", "B", "T", 1)
+	check([]string{"S", "A", "B", "T"}, 4)
+
+	// without U-turns at D, the detour goes around the block and through B again
+	turns.Forbid("A", "B", "T")
+	turns.Forbid("B", "D", "B")
+	check([]string{"S", "A", "C", "D", "B", "T"}, 5)
+
+	turns.Forbid("D", "B", "T")
+	if _, valid := TurnAwareDijkstra(g, turns, "S", "T", dijkstrastructs.EmptyUnusableEdgeMap()); valid {
+		t.Fatal("A path was found through forbidden turns.")
+	}
+}
+
+func TestParallelEdges(t *testing.T) {
+	g := graphtest.NewGraph()
+	g.AddEdge("S", "A", "s-a", 1)
+	g.AddEdge("A", "T", "slow", 5)
+	g.AddEdge("A", "T", "fast", 2)
+	g.AddEdge("A", "T", "medium", 3)
+
+	check := func(path dijkstrapath.DijkstraPath, valid bool, id string, weight int) {
+		if !valid {
+			t.Fatal("Validity error.")
+		}
+		if len(path.Path) != 3 || path.Path[1].EdgeID != "s-a" || path.Path[2].EdgeID != id {
+			t.Fatalf("Wrong path: %v", path.Path)
+		}
+		if path.Weight != weight {
+			t.Fatalf("Wrong path weight:\nExpected: %d\nGot: %d\n", weight, path.Weight)
+		}
+	}
+
+	for _, searchType := range []int{VANILLA, BIDIR} {
+		path, valid := SearchPath(g, "S", "T", searchType)
+		check(path, valid, "fast", 3)
+	}
+
+	for _, search := range []func(dijkstrastructs.GraphObject, string, string, dijkstrastructs.UnusableEdgeMap) (dijkstrapath.DijkstraPath, bool){Dijkstra, BiDirDijkstra} {
+		banned := dijkstrastructs.EmptyUnusableEdgeMap()
+		banned.BanEdge("A", "T", "fast")
+		path, valid := search(g, "S", "T", banned)
+		check(path, valid, "medium", 4)
+
+		banned.BanEdge("A", "T", "medium")
+		path, valid = search(g, "S", "T", banned)
+		check(path, valid, "slow", 6)
+
+		banned.Ban("A", "T")
+		if _, valid := search(g, "S", "T", banned); valid {
+			t.Fatal("A banned edge was used.")
+		}
+	}
+}
+
+func TestEdgeAttributes(t *testing.T) {
+	g := graphtest.NewGraph()
+	g.AddEdgeWithAttributes("S", "A", "1", 2, map[string]interface{}{"name": "Main St"})
+	g.AddEdgeWithAttributes("A", "B", "2", 3, map[string]interface{}{"name": "Oak Ave"})
+	g.AddEdgeWithAttributes("B", "T", "3", 1, map[string]interface{}{"name": "Elm St"})
+	g.AddEdge("A", "T", "4", 10)
+
+	expEdges := []dijkstrapath.DijkstraPathEdge{
+		{From: "S", To: "A", ID: "1", Weight: 2, Attributes: map[string]interface{}{"name": "Main St"}},
+		{From: "A", To: "B", ID: "2", Weight: 3, Attributes: map[string]interface{}{"name": "Oak Ave"}},
+		{From: "B", To: "T", ID: "3", Weight: 1, Attributes: map[string]interface{}{"name": "Elm St"}},
+	}
+	for _, searchType := range []int{VANILLA, BIDIR} {
+		path, valid := SearchPath(g, "S", "T", searchType)
+		if !valid {
+			t.Fatal("Validity error.")
+		}
+		edges := path.Edges()
+		if len(edges) != len(expEdges) {
+			t.Fatalf("Wrong edges: %v", edges)
+		}
+		for i, e := range edges {
+			exp := expEdges[i]
+			if e.From != exp.From || e.To != exp.To || e.ID != exp.ID || e.Weight != exp.Weight || e.Attributes["name"] != exp.Attributes["name"] {
+				t.Fatalf("Wrong edge (%d):\nExpected: %v\nGot: %v\n", i, exp, e)
+			}
+		}
+	}
+}
+
+func TestCostProfile(t *testing.T) {
+	g := graphtest.NewGraph()
+	g.AddEdgeWithAttributes("S", "A", "1", 2, map[string]interface{}{"highway": true})
+	g.AddEdgeWithAttributes("A", "T", "2", 2, map[string]interface{}{"highway": true})
+	g.AddEdge("S", "B", "3", 3)
+	g.AddEdge("B", "T", "4", 3)
+
+	// bikes cannot use highways and are slowed down by hills
+	bike := func(from string, c dijkstrastructs.Connection) (int, bool) {
+		if c.Attributes["highway"] == true {
+			return 0, false
+		}
+		if from == "B" {
+			return c.Weight * 2, true
+		}
+		return c.Weight, true
+	}
+
+	for _, searchType := range []int{VANILLA, BIDIR} {
+		car, valid := SearchPathWithOptions(g, "S", "T", searchType, SearchOptions{})
+		if !valid || car.Weight != 4 || car.Path[1].Node != "A" {
+			t.Fatalf("Wrong car path: %v", car.Path)
+		}
+		path, valid := SearchPathWithOptions(g, "S", "T", searchType, SearchOptions{Cost: bike})
+		if !valid || path.Weight != 9 || path.Path[1].Node != "B" {
+			t.Fatalf("Wrong bike path: %v", path.Path)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	g := graphtest.NewGraph()
+	g.AddEdge("S", "A", "", 1)
+	g.AddEdge("A", "T", "", 2)
+	nodes := []string{"S", "A", "T"}
+	if r := Validate(g, nodes); !r.Valid() {
+		t.Fatalf("Issues found in a consistent graph: %v", r.Issues)
+	}
+
+	g.AddEdge("S", "T", "", -4)
+	g.AddSuccessor("A", "S", "", 1)
+	g.AddPredecessor("T", "A", "", 1)
+	g.RemoveEdges("A", "T")
+	g.AddSuccessor("A", "T", "", 2)
+	g.AddPredecessor("A", "T", "", 3)
+
+	r := Validate(g, nodes)
+	expIssues := map[string]IssueKind{
+		"S-T": NegativeWeight,
+		"A-S": MissingPredecessor,
+		"T-A": MissingSuccessor,
+		"A-T": WeightMismatch,
+	}
+	found := make(map[string]bool)
+	for _, i := range r.Issues {
+		key := i.From + "-" + i.To
+		if expIssues[key] != i.Kind {
+			t.Fatalf("Unexpected issue: %v", i)
+		}
+		found[key] = true
+	}
+	if len(found) != len(expIssues) {
+		t.Fatalf("Missing issues: %v", r.Issues)
+	}
+
+	g2 := graphtest.NewGraph()
+	g2.AddEdge("S", "T", "", 2)
+	r = Validate(&skewedTestGraph{g2, 3}, []string{"S", "T"})
+	if len(r.Issues) != 1 || r.Issues[0].Kind != EdgeWeightMismatch || r.Issues[0].Weight != 2 || r.Issues[0].Expected != 5 {
+		t.Fatalf("Wrong issues: %v", r.Issues)
+	}
+
+	// parallel edges without identifiers, listed in a different order on the two sides
+	g3 := graphtest.NewGraph()
+	g3.AddSuccessor("S", "T", "", 2)
+	g3.AddSuccessor("S", "T", "", 5)
+	g3.AddPredecessor("S", "T", "", 5)
+	g3.AddPredecessor("S", "T", "", 2)
+	if r = Validate(g3, []string{"S", "T"}); !r.Valid() {
+		t.Fatalf("Issues found on consistent parallel edges: %v", r.Issues)
+	}
+	g3.RemoveEdges("S", "T")
+	g3.AddSuccessor("S", "T", "", 2)
+	g3.AddSuccessor("S", "T", "", 5)
+	g3.AddPredecessor("S", "T", "", 2)
+	g3.AddPredecessor("S", "T", "", 2)
+	r = Validate(g3, []string{"S", "T"})
+	if len(r.Issues) != 1 || r.Issues[0].Kind != WeightMismatch || r.Issues[0].Weight != 5 || r.Issues[0].Expected != 2 {
+		t.Fatalf("Wrong issues on parallel edges: %v", r.Issues)
+	}
+}
+
+func TestDebugNegativeWeight(t *testing.T) {
+	g := newTestGraph()
+	g.addEdge("S", "A", 1)
+	g.addEdge("A", "T", -1)
+
+	defer func() {
+		err, ok := recover().(*NegativeWeightError)
+		if !ok {
+			t.Fatal("Negative weight not detected.")
+		}
+		if err.From != "A" || err.To != "T" || err.Weight != -1 {
+			t.Fatalf("Wrong error: %v", err)
+		}
+	}()
+	SearchPathWithOptions(g, "S", "T", VANILLA, SearchOptions{Debug: true})
+}
+
+func TestTieBreak(t *testing.T) {
+	// three paths with the same weight and number of hops
+	g := newTestGraph()
+	for _, n := range []string{"C", "B", "A"} {
+		g.addEdge("S", n, 1)
+		g.addEdge(n, "T", 1)
+	}
+	g.addEdge("S", "T", 2)
+
+	opts := SearchOptions{TieBreak: TieBreakHops}
+	for _, searchType := range []int{VANILLA, BIDIR} {
+		first, valid := SearchPathWithOptions(g, "S", "T", searchType, opts)
+		if !valid {
+			t.Fatalf("Path not found (%d)", searchType)
+		}
+		for i := 0; i < 20; i++ {
+			p, _ := SearchPathWithOptions(g, "S", "T", searchType, opts)
+			if !p.IsEqual(first) {
+				t.Fatalf("Non reproducible path (%d):\nExpected: %v\nGot: %v\n", searchType, first.Path, p.Path)
+			}
+		}
+	}
+	p, _ := DijkstraWithOptions(g, "S", "T", dijkstrastructs.EmptyUnusableEdgeMap(), opts)
+	if len(p.Path) != 2 {
+		t.Fatalf("Expected the path with fewer hops, got: %v", p.Path)
+	}
+
+	// without the direct edge the lexicographically smallest path wins
+	delete(g.edges["S"], "T")
+	delete(g.reverseEdges["T"], "S")
+	p, _ = DijkstraWithOptions(g, "S", "T", dijkstrastructs.EmptyUnusableEdgeMap(), opts)
+	expPath := []string{"S", "A", "T"}
+	for i, v := range p.Path {
+		if v.Node != expPath[i] {
+			t.Fatalf("Wrong path: %v", p.Path)
+		}
+	}
+
+	// insertion order follows the order of the successors
+	mg := graphtest.NewGraph()
+	for _, n := range []string{"C", "B", "A"} {
+		mg.AddEdge("S", n, "", 1)
+		mg.AddEdge(n, "T", "", 1)
+	}
+	p, _ = DijkstraWithOptions(mg, "S", "T", dijkstrastructs.EmptyUnusableEdgeMap(), SearchOptions{TieBreak: TieBreakInsertion})
+	if p.Path[1].Node != "C" {
+		t.Fatalf("Wrong path: %v", p.Path)
+	}
+}
+
+func TestIndexedHeap(t *testing.T) {
+	g := newGridTestGraph(15, 1)
+	r := rand.New(rand.NewSource(2))
+	for i := 0; i < 50; i++ {
+		s, e := gridNode(r.Intn(15), r.Intn(15)), gridNode(r.Intn(15), r.Intn(15))
+		exp, _ := Dijkstra(g, s, e, dijkstrastructs.EmptyUnusableEdgeMap())
+		for _, tb := range []TieBreakMode{TieBreakNone, TieBreakHops, TieBreakInsertion} {
+			p, valid := DijkstraWithOptions(g, s, e, dijkstrastructs.EmptyUnusableEdgeMap(), SearchOptions{Queue: IndexedHeap, TieBreak: tb})
+			if !valid || p.Weight != exp.Weight {
+				t.Fatalf("Wrong path weight %s -> %s (%d):\nExpected: %d\nGot: %d\n", s, e, tb, exp.Weight, p.Weight)
+			}
+			if tb == TieBreakHops {
+				lp, _ := DijkstraWithOptions(g, s, e, dijkstrastructs.EmptyUnusableEdgeMap(), SearchOptions{TieBreak: tb})
+				if !p.IsEqual(lp) {
+					t.Fatalf("Different paths %s -> %s:\nLazy: %v\nIndexed: %v\n", s, e, lp.Path, p.Path)
+				}
+			}
+		}
+	}
+}
+
+func TestDial(t *testing.T) {
+	exp, _ := SearchPath(graph, "S", "T", VANILLA)
+	p, valid := SearchPath(graph, "S", "T", DIAL)
+	if !valid || p.Weight != exp.Weight {
+		t.Fatalf("Wrong path weight:\nExpected: %d\nGot: %d\n", exp.Weight, p.Weight)
+	}
+
+	g := newGridTestGraph(15, 3)
+	// heavy and zero weight edges make the buckets grow and wrap around
+	g.AddEdge(gridNode(0, 0), gridNode(14, 14), "", 1000)
+	g.AddEdge(gridNode(7, 7), gridNode(7, 8), "", 0)
+	r := rand.New(rand.NewSource(4))
+	for i := 0; i < 50; i++ {
+		s, e := gridNode(r.Intn(15), r.Intn(15)), gridNode(r.Intn(15), r.Intn(15))
+		exp, _ := Dijkstra(g, s, e, dijkstrastructs.EmptyUnusableEdgeMap())
+		p, valid := DialDijkstra(g, s, e, dijkstrastructs.EmptyUnusableEdgeMap())
+		if !valid || p.Weight != exp.Weight {
+			t.Fatalf("Wrong path weight %s -> %s:\nExpected: %d\nGot: %d\n", s, e, exp.Weight, p.Weight)
+		}
+		w := 0
+		for _, edge := range p.Edges() {
+			w += edge.Weight
+		}
+		if w != p.Weight {
+			t.Fatalf("Inconsistent path %s -> %s: %v", s, e, p.Path)
+		}
+	}
+
+	hp, _ := SearchPathWithOptions(graph, "S", "T", DIAL, SearchOptions{MaxHops: 4})
+	if exp, _ := HopLimitedDijkstra(graph, "S", "T", dijkstrastructs.EmptyUnusableEdgeMap(), 4); hp.Weight != exp.Weight {
+		t.Fatalf("Wrong hop-limited path weight:\nExpected: %d\nGot: %d\n", exp.Weight, hp.Weight)
+	}
+}
+
+func TestSearcher(t *testing.T) {
+	g := newGridTestGraph(15, 5)
+	r := rand.New(rand.NewSource(6))
+	for _, opts := range []SearchOptions{SearchOptions{}, SearchOptions{Queue: IndexedHeap, TieBreak: TieBreakHops}, SearchOptions{MaxHops: 20}} {
+		searcher := NewSearcher(opts)
+		var first, firstCopy dijkstrapath.DijkstraPath
+		for i := 0; i < 30; i++ {
+			s, e := gridNode(r.Intn(15), r.Intn(15)), gridNode(r.Intn(15), r.Intn(15))
+			for _, searchType := range []int{VANILLA, BIDIR, DIAL} {
+				exp, expValid := SearchPathWithOptions(g, s, e, searchType, opts)
+				p, valid := searcher.SearchPath(g, s, e, searchType)
+				if valid != expValid || p.Weight != exp.Weight {
+					t.Fatalf("Wrong path %s -> %s (%d):\nExpected: %v\nGot: %v\n", s, e, searchType, exp.Path, p.Path)
+				}
+				if i == 0 && searchType == VANILLA {
+					first = p
+					firstCopy = dijkstrapath.DijkstraPath{Path: append([]dijkstrapath.DijkstraPathElement(nil), p.Path...), Weight: p.Weight}
+				}
+			}
+		}
+		// paths are not affected by later searches
+		if !first.IsEqual(firstCopy) || first.Weight != firstCopy.Weight {
+			t.Fatalf("Path changed by a later search:\nExpected: %v\nGot: %v\n", firstCopy.Path, first.Path)
+		}
+	}
+}
+
+func TestOneShotAllocs(t *testing.T) {
+	// a one-shot search over a few nodes must allocate in proportion to them, without any workspace block
+	g := graphtest.NewGraph()
+	g.AddEdge("S", "A", "", 1)
+	g.AddEdge("A", "B", "", 1)
+	g.AddEdge("B", "T", "", 1)
+	g.AddEdge("S", "T", "", 5)
+	const runs = 100
+	for _, search := range []func(dijkstrastructs.GraphObject, string, string, dijkstrastructs.UnusableEdgeMap) (dijkstrapath.DijkstraPath, bool){Dijkstra, BiDirDijkstra} {
+		f := func() {
+			search(g, "S", "T", dijkstrastructs.EmptyUnusableEdgeMap())
+		}
+		allocs := testing.AllocsPerRun(runs, f)
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		for i := 0; i < runs; i++ {
+			f()
+		}
+		runtime.ReadMemStats(&after)
+		bytes := (after.TotalAlloc - before.TotalAlloc) / runs
+		// a block of candidateBlockSize candidates alone takes 16 KiB
+		if allocs > 40 || bytes > 4096 {
+			t.Fatalf("Too many allocations: %v allocs, %d bytes per search", allocs, bytes)
+		}
+	}
+}
+
+func TestBFS(t *testing.T) {
+	for _, searchType := range []int{BFS, BIDIR_BFS} {
+		exp, _ := SearchPath(graph, "S", "T", VANILLA)
+		p, valid := SearchPath(graph, "S", "T", searchType)
+		if !valid || p.Weight != exp.Weight {
+			t.Fatalf("Wrong path weight (%d):\nExpected: %d\nGot: %d\n", searchType, exp.Weight, p.Weight)
+		}
+		if p, valid := SearchPath(graph, "S", "S", searchType); !valid || len(p.Path) != 1 {
+			t.Fatalf("Wrong path from a node to itself (%d): %v", searchType, p.Path)
+		}
+		if _, valid := SearchPath(graph, "T", "S", searchType); valid {
+			t.Fatalf("Found path between unconnected nodes (%d)", searchType)
+		}
+	}
+
+	g := newGridTestGraph(15, 7)
+	r := rand.New(rand.NewSource(8))
+	searcher := NewSearcher(SearchOptions{})
+	for i := 0; i < 50; i++ {
+		si, sj, ei, ej := r.Intn(15), r.Intn(15), r.Intn(15), r.Intn(15)
+		hops := abs(si-ei) + abs(sj-ej)
+		s, e := gridNode(si, sj), gridNode(ei, ej)
+		for _, searchType := range []int{BFS, BIDIR_BFS} {
+			for _, p := range []dijkstrapath.DijkstraPath{first(SearchPath(g, s, e, searchType)), first(searcher.SearchPath(g, s, e, searchType))} {
+				if p.Weight != hops || len(p.Path) != hops+1 || p.Path[len(p.Path)-1].Weight != hops {
+					t.Fatalf("Wrong path %s -> %s (%d):\nExpected: %d hops\nGot: %v\n", s, e, searchType, hops, p.Path)
+				}
+			}
+		}
+	}
+
+	// banned edges are honored
+	banned := dijkstrastructs.EmptyUnusableEdgeMap()
+	banned.Ban(gridNode(0, 0), gridNode(0, 1))
+	for _, search := range []func(dijkstrastructs.GraphObject, string, string, dijkstrastructs.UnusableEdgeMap) (dijkstrapath.DijkstraPath, bool){BreadthFirstSearch, BiDirBreadthFirstSearch} {
+		p, _ := search(g, gridNode(0, 0), gridNode(0, 1), banned)
+		if p.Weight != 3 {
+			t.Fatalf("Wrong path avoiding a banned edge: %v", p.Path)
+		}
+	}
+
+	// unweighted graphs are searched breadth-first
+	ug := &unweightedTestGraph{g}
+	s, e := gridNode(0, 0), gridNode(14, 14)
+	for _, searchType := range []int{VANILLA, BIDIR, DIAL} {
+		if p, _ := SearchPath(ug, s, e, searchType); p.Weight != 28 {
+			t.Fatalf("Unweighted graph not searched breadth-first (%d): %v", searchType, p.Path)
+		}
+		if p, _ := searcher.SearchPath(ug, s, e, searchType); p.Weight != 28 {
+			t.Fatalf("Unweighted graph not searched breadth-first by Searcher (%d): %v", searchType, p.Path)
+		}
+	}
+	opts := SearchOptions{Cost: func(from string, c dijkstrastructs.Connection) (int, bool) {
+		return c.Weight, true
+	}}
+	exp, _ := Dijkstra(g, s, e, dijkstrastructs.EmptyUnusableEdgeMap())
+	if p, _ := SearchPathWithOptions(ug, s, e, VANILLA, opts); p.Weight != exp.Weight {
+		t.Fatalf("Cost function ignored on an unweighted graph:\nExpected: %d\nGot: %d\n", exp.Weight, p.Weight)
+	}
+}
+
+func TestZeroOneBFS(t *testing.T) {
+	g := graphtest.NewGraph()
+	grid := newGridTestGraph(15, 9)
+	for _, n := range grid.Nodes() {
+		for _, c := range grid.SuccessorsForNode(n) {
+			g.AddEdge(n, c.Destination, "", c.Weight%2)
+		}
+	}
+	r := rand.New(rand.NewSource(10))
+	searcher := NewSearcher(SearchOptions{})
+	for i := 0; i < 50; i++ {
+		s, e := gridNode(r.Intn(15), r.Intn(15)), gridNode(r.Intn(15), r.Intn(15))
+		exp, _ := Dijkstra(g, s, e, dijkstrastructs.EmptyUnusableEdgeMap())
+		for _, p := range []dijkstrapath.DijkstraPath{first(SearchPath(g, s, e, ZERO_ONE_BFS)), first(searcher.SearchPath(g, s, e, ZERO_ONE_BFS))} {
+			if p.Weight != exp.Weight || p.Path[len(p.Path)-1].Node != e {
+				t.Fatalf("Wrong path %s -> %s:\nExpected: %d\nGot: %v\n", s, e, exp.Weight, p.Path)
+			}
+			w := 0
+			for _, edge := range p.Edges() {
+				w += edge.Weight
+			}
+			if w != p.Weight {
+				t.Fatalf("Inconsistent path %s -> %s: %v", s, e, p.Path)
+			}
+		}
+	}
+
+	// other weights make the search fall back to the Dijkstra algorithm
+	g.AddEdge(gridNode(0, 0), gridNode(5, 5), "", 2)
+	g.AddEdge(gridNode(5, 5), gridNode(14, 14), "", 0)
+	s, e := gridNode(0, 0), gridNode(14, 14)
+	exp, _ := Dijkstra(g, s, e, dijkstrastructs.EmptyUnusableEdgeMap())
+	for _, p := range []dijkstrapath.DijkstraPath{first(ZeroOneBFS(g, s, e, dijkstrastructs.EmptyUnusableEdgeMap())), first(searcher.SearchPath(g, s, e, ZERO_ONE_BFS))} {
+		if p.Weight != exp.Weight || p.Path[len(p.Path)-1].Node != e {
+			t.Fatalf("Wrong path %s -> %s with a weight 2 edge:\nExpected: %d\nGot: %v\n", s, e, exp.Weight, p.Path)
+		}
+	}
+}
+
+func TestBiDirZeroWeight(t *testing.T) {
+	g := graphtest.NewGraph()
+	g.AddEdge("S", "A", "", 0)
+	g.AddEdge("A", "T", "", 0)
+	g.AddEdge("S", "T", "", 1)
+	for _, opts := range []SearchOptions{SearchOptions{}, SearchOptions{Queue: IndexedHeap}} {
+		p, valid := BiDirDijkstraWithOptions(g, "S", "T", dijkstrastructs.EmptyUnusableEdgeMap(), opts)
+		if !valid || p.Weight != 0 || len(p.Path) != 3 {
+			t.Fatalf("Wrong zero weight path: %v", p.Path)
+		}
+		p, valid = BiDirDijkstraWithOptions(g, "S", "S", dijkstrastructs.EmptyUnusableEdgeMap(), opts)
+		if !valid || p.Weight != 0 || len(p.Path) != 1 {
+			t.Fatalf("Wrong path from a node to itself: %v", p.Path)
+		}
+	}
+}
+
+func TestBiDirRandom(t *testing.T) {
+	for seed := int64(0); seed < 30; seed++ {
+		g := newRandomTestGraph(40, 120, int(seed%4)*5, seed)
+		r := rand.New(rand.NewSource(seed))
+		for i := 0; i < 20; i++ {
+			s, e := randomNode(r.Intn(40)), randomNode(r.Intn(40))
+			banned := dijkstrastructs.EmptyUnusableEdgeMap()
+			for _, c := range g.SuccessorsForNode(s) {
+				if r.Intn(3) == 0 {
+					banned.BanEdge(s, c.Destination, c.ID)
+				}
+			}
+			exp, expValid := Dijkstra(g, s, e, banned)
+			for _, opts := range []SearchOptions{SearchOptions{}, SearchOptions{Queue: IndexedHeap}, SearchOptions{TieBreak: TieBreakHops}} {
+				p, valid := BiDirDijkstraWithOptions(g, s, e, banned, opts)
+				if valid != expValid || p.Weight != exp.Weight {
+					t.Fatalf("Wrong path %s -> %s (seed %d):\nExpected: %v %v\nGot: %v %v\n", s, e, seed, expValid, exp.Path, valid, p.Path)
+				}
+				if !valid {
+					continue
+				}
+				w := 0
+				for _, edge := range p.Edges() {
+					found := false
+					for _, c := range g.SuccessorsForNode(edge.From) {
+						found = found || c.Destination == edge.To && c.ID == edge.ID && c.Weight == edge.Weight
+					}
+					if !found || banned.IsBanned(edge.From, edge.To, edge.ID) {
+						t.Fatalf("Invalid edge %v in path %s -> %s (seed %d)", edge, s, e, seed)
+					}
+					w += edge.Weight
+				}
+				if w != p.Weight || p.Path[0].Node != s || p.Path[len(p.Path)-1].Node != e {
+					t.Fatalf("Inconsistent path %s -> %s (seed %d): %v", s, e, seed, p.Path)
+				}
+			}
+		}
+	}
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func first(p dijkstrapath.DijkstraPath, valid bool) dijkstrapath.DijkstraPath {
+	return p
+}
+
+func benchmarkQueue(b *testing.B, kind QueueKind) {
+	g := newGridTestGraph(100, 1)
+	s, e := gridNode(0, 0), gridNode(99, 99)
+	opts := SearchOptions{Queue: kind}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		DijkstraWithOptions(g, s, e, dijkstrastructs.EmptyUnusableEdgeMap(), opts)
+	}
+}
+
+func BenchmarkLazyHeap(b *testing.B) {
+	benchmarkQueue(b, LazyHeap)
+}
+
+func BenchmarkIndexedHeap(b *testing.B) {
+	benchmarkQueue(b, IndexedHeap)
+}
+
+func BenchmarkBucketQueue(b *testing.B) {
+	benchmarkQueue(b, BucketQueue)
+}
+
+func BenchmarkSearcher(b *testing.B) {
+	g := newGridTestGraph(100, 1)
+	s, e := gridNode(0, 0), gridNode(99, 99)
+	searcher := NewSearcher(SearchOptions{})
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		searcher.Dijkstra(g, s, e, dijkstrastructs.EmptyUnusableEdgeMap())
+	}
+}
@@ -17,9 +17,22 @@ limitations under the License.
 package dijkstra
 
 import (
+	"container/heap"
 	"github.com/kirves/godijkstra/common/structs"
 )
 
+// candidateQueue is the open list of a search, extracting candidates by increasing weight.
+type candidateQueue interface {
+	Len() int
+	push(c *dijkstrastructs.DijkstraCandidate)
+	pop() *dijkstrastructs.DijkstraCandidate
+	// improves reports whether a candidate reaching node with weight w may be extracted before
+	// the one already queued for node, so that candidates that cannot be are never allocated.
+	improves(node string, w int) bool
+	// reset empties the queue to be used by a new search.
+	reset()
+}
+
 // DijkstraQueue is a collecition of DijkstraCandidate elements.
 // It implements the heap.Interface interface to be used as a heap.
 type DijkstraQueue []*dijkstrastructs.DijkstraCandidate
@@ -47,3 +60,108 @@ func (pq *DijkstraQueue) Pop() interface{} {
 	*pq = old[0 : n-1]
 	return x
 }
+
+func (pq *DijkstraQueue) push(c *dijkstrastructs.DijkstraCandidate) {
+	heap.Push(pq, c)
+}
+
+func (pq *DijkstraQueue) pop() *dijkstrastructs.DijkstraCandidate {
+	return heap.Pop(pq).(*dijkstrastructs.DijkstraCandidate)
+}
+
+func (pq *DijkstraQueue) improves(node string, w int) bool {
+	return true
+}
+
+func (pq *DijkstraQueue) reset() {
+	*pq = (*pq)[:0]
+}
+
+// orderedItem is a candidate stored in an orderedQueue along with its insertion sequence number.
+type orderedItem struct {
+	candidate *dijkstrastructs.DijkstraCandidate
+	seq       int
+}
+
+// orderedQueue is a heap of candidates that breaks weight ties according to a TieBreakMode.
+type orderedQueue struct {
+	items []orderedItem
+	mode  TieBreakMode
+	seq   int
+}
+
+func newOrderedQueue(mode TieBreakMode) *orderedQueue {
+	return &orderedQueue{mode: mode}
+}
+
+func (q *orderedQueue) Len() int {
+	return len(q.items)
+}
+
+func (q *orderedQueue) Less(i, j int) bool {
+	return lessItem(q.mode, q.items[i], q.items[j])
+}
+
+func (q *orderedQueue) Swap(i, j int) {
+	q.items[i], q.items[j] = q.items[j], q.items[i]
+}
+
+func (q *orderedQueue) Push(x interface{}) {
+	q.items = append(q.items, x.(orderedItem))
+}
+
+func (q *orderedQueue) Pop() interface{} {
+	n := len(q.items)
+	x := q.items[n-1]
+	q.items = q.items[0 : n-1]
+	return x
+}
+
+func (q *orderedQueue) push(c *dijkstrastructs.DijkstraCandidate) {
+	heap.Push(q, orderedItem{c, q.seq})
+	q.seq++
+}
+
+func (q *orderedQueue) pop() *dijkstrastructs.DijkstraCandidate {
+	return heap.Pop(q).(orderedItem).candidate
+}
+
+func (q *orderedQueue) improves(node string, w int) bool {
+	return true
+}
+
+func (q *orderedQueue) reset() {
+	q.items = q.items[:0]
+	q.seq = 0
+}
+
+// lessItem orders two queue items by weight, breaking ties according to mode.
+func lessItem(mode TieBreakMode, a, b orderedItem) bool {
+	if a.candidate.Weight != b.candidate.Weight {
+		return a.candidate.Weight < b.candidate.Weight
+	}
+	switch mode {
+	case TieBreakHops:
+		return lessCandidate(a.candidate, b.candidate)
+	case TieBreakInsertion:
+		return a.seq < b.seq
+	}
+	return false
+}
+
+// lessCandidate orders two candidates with the same weight by number of hops, then by comparing
+// the nodes and edge identifiers of their paths, starting from the last node and walking back to the root.
+func lessCandidate(a, b *dijkstrastructs.DijkstraCandidate) bool {
+	if a.Hops != b.Hops {
+		return a.Hops < b.Hops
+	}
+	for ; a != nil && b != nil && a != b; a, b = a.Parent, b.Parent {
+		if a.Node != b.Node {
+			return a.Node < b.Node
+		}
+		if a.EdgeID != b.EdgeID {
+			return a.EdgeID < b.EdgeID
+		}
+	}
+	return false
+}
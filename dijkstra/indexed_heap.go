@@ -0,0 +1,118 @@
+/*
+Copyright 2013 Alessandro Frossi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dijkstra
+
+import (
+	"github.com/kirves/godijkstra/common/structs"
+)
+
+// heapArity is the number of children of each node of an indexedQueue.
+// A 4-ary heap is shallower than a binary one, making decrease-key cheaper at a small cost for extraction.
+const heapArity = 4
+
+// indexedQueue is a d-ary heap holding at most one candidate per node.
+// Pushing a candidate for a node already in the queue replaces it only if the new one comes first,
+// moving it up the heap (decrease-key); otherwise the new candidate is discarded.
+type indexedQueue struct {
+	items []orderedItem
+	index map[string]int // Position of each node within items
+	mode  TieBreakMode
+	seq   int
+}
+
+func newIndexedQueue(mode TieBreakMode) *indexedQueue {
+	return &indexedQueue{index: make(map[string]int), mode: mode}
+}
+
+func (q *indexedQueue) Len() int {
+	return len(q.items)
+}
+
+func (q *indexedQueue) push(c *dijkstrastructs.DijkstraCandidate) {
+	item := orderedItem{c, q.seq}
+	q.seq++
+	if i, ok := q.index[c.Node]; ok {
+		if !lessItem(q.mode, item, q.items[i]) {
+			return
+		}
+		q.items[i] = item
+		q.up(i)
+		return
+	}
+	q.items = append(q.items, item)
+	q.index[c.Node] = len(q.items) - 1
+	q.up(len(q.items) - 1)
+}
+
+func (q *indexedQueue) pop() *dijkstrastructs.DijkstraCandidate {
+	top := q.items[0]
+	n := len(q.items) - 1
+	q.swap(0, n)
+	q.items = q.items[0:n]
+	delete(q.index, top.candidate.Node)
+	if n > 0 {
+		q.down(0)
+	}
+	return top.candidate
+}
+
+func (q *indexedQueue) improves(node string, w int) bool {
+	i, ok := q.index[node]
+	return !ok || w <= q.items[i].candidate.Weight
+}
+
+func (q *indexedQueue) reset() {
+	q.items = q.items[:0]
+	for k := range q.index {
+		delete(q.index, k)
+	}
+	q.seq = 0
+}
+
+func (q *indexedQueue) swap(i, j int) {
+	q.items[i], q.items[j] = q.items[j], q.items[i]
+	q.index[q.items[i].candidate.Node] = i
+	q.index[q.items[j].candidate.Node] = j
+}
+
+func (q *indexedQueue) up(i int) {
+	for i > 0 {
+		p := (i - 1) / heapArity
+		if !lessItem(q.mode, q.items[i], q.items[p]) {
+			break
+		}
+		q.swap(i, p)
+		i = p
+	}
+}
+
+func (q *indexedQueue) down(i int) {
+	n := len(q.items)
+	for {
+		min := i
+		for c := heapArity*i + 1; c <= heapArity*i+heapArity && c < n; c++ {
+			if lessItem(q.mode, q.items[c], q.items[min]) {
+				min = c
+			}
+		}
+		if min == i {
+			break
+		}
+		q.swap(i, min)
+		i = min
+	}
+}
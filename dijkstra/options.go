@@ -0,0 +1,129 @@
+/*
+Copyright 2013 Alessandro Frossi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dijkstra
+
+import (
+	"github.com/kirves/godijkstra/common/structs"
+)
+
+// CostFunc computes the weight of the edge c going from node from for a given search.
+// It returns false if the edge cannot be used at all.
+type CostFunc func(from string, c dijkstrastructs.Connection) (weight int, allowed bool)
+
+// TieBreakMode defines how a search orders candidates with the same weight.
+type TieBreakMode int
+
+const (
+	TieBreakNone      TieBreakMode = iota // Candidates with the same weight are extracted in no particular order
+	TieBreakHops                          // Prefer fewer hops, then compare node names from the last node back to the start
+	TieBreakInsertion                     // Prefer the candidate found first; reproducible only if the graph lists successors in a fixed order
+)
+
+// QueueKind selects the priority queue used as open list by a search.
+type QueueKind int
+
+const (
+	LazyHeap    QueueKind = iota // Binary heap holding a candidate per relaxed edge, stale candidates are skipped when extracted
+	IndexedHeap                  // 4-ary heap holding a candidate per node, updated in place by decrease-key
+	BucketQueue                  // Dial's buckets, one per weight, suited to small integer edge weights; ignored if TieBreak is set
+)
+
+// SearchOptions defines optional constraints and settings for a path search.
+// The zero value leaves the search unconstrained.
+type SearchOptions struct {
+	MaxHops  int          // Maximum number of edges of the returned path (0 means no limit)
+	Cost     CostFunc     // Edge weights used by the search in place of Connection.Weight (nil means graph weights)
+	Debug    bool         // Panic with a *NegativeWeightError as soon as a negative edge weight is found
+	TieBreak TieBreakMode // Order of the candidates with the same weight, making the returned path reproducible
+	Queue    QueueKind    // Priority queue used by the search; hop-limited searches always use LazyHeap
+}
+
+// newQueue returns an empty open list for a search run with the options.
+func (opts SearchOptions) newQueue() candidateQueue {
+	if opts.Queue == IndexedHeap {
+		return newIndexedQueue(opts.TieBreak)
+	}
+	if opts.TieBreak != TieBreakNone {
+		return newOrderedQueue(opts.TieBreak)
+	}
+	if opts.Queue == BucketQueue {
+		return newBucketQueue()
+	}
+	return &DijkstraQueue{}
+}
+
+// newLazyQueue returns an empty open list able to hold several candidates for the same node.
+func (opts SearchOptions) newLazyQueue() candidateQueue {
+	if opts.Queue == IndexedHeap {
+		opts.Queue = LazyHeap
+	}
+	return opts.newQueue()
+}
+
+// graph returns the graph object seen by a search run with the options.
+func (opts SearchOptions) graph(graph dijkstrastructs.GraphObject) dijkstrastructs.GraphObject {
+	if opts.Cost != nil {
+		graph = &costGraph{graph, opts.Cost}
+	}
+	if opts.Debug {
+		graph = &checkedGraph{graph}
+	}
+	return graph
+}
+
+// costGraph is a GraphObject whose edge weights are computed by a CostFunc.
+type costGraph struct {
+	graph dijkstrastructs.GraphObject
+	cost  CostFunc
+}
+
+func (cg *costGraph) SuccessorsForNode(node string) []dijkstrastructs.Connection {
+	tmp := cg.graph.SuccessorsForNode(node)
+	ret := make([]dijkstrastructs.Connection, 0, len(tmp))
+	for _, s := range tmp {
+		if w, ok := cg.cost(node, s); ok {
+			s.Weight = w
+			ret = append(ret, s)
+		}
+	}
+	return ret
+}
+
+func (cg *costGraph) PredecessorsFromNode(node string) []dijkstrastructs.Connection {
+	tmp := cg.graph.PredecessorsFromNode(node)
+	ret := make([]dijkstrastructs.Connection, 0, len(tmp))
+	for _, p := range tmp {
+		// the cost function sees the edge in its forward direction
+		c := p
+		c.Destination = node
+		if w, ok := cg.cost(p.Destination, c); ok {
+			p.Weight = w
+			ret = append(ret, p)
+		}
+	}
+	return ret
+}
+
+func (cg *costGraph) EdgeWeight(n1, n2 string) int {
+	w, found := 0, false
+	for _, s := range cg.SuccessorsForNode(n1) {
+		if s.Destination == n2 && (!found || s.Weight < w) {
+			w, found = s.Weight, true
+		}
+	}
+	return w
+}
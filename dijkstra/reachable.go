@@ -0,0 +1,93 @@
+/*
+Copyright 2013 Alessandro Frossi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dijkstra
+
+import (
+	"container/heap"
+	"github.com/kirves/godijkstra/common/structs"
+)
+
+// ReachableNode is a node reached by a bounded search.
+type ReachableNode struct {
+	Weight int    // Weight of the shortest path from the source node
+	Parent string // Previous node on the shortest path (empty for the source node)
+}
+
+// BoundaryEdge is an edge going from a node reachable within the budget to a node that is not.
+type BoundaryEdge struct {
+	From       string // Reachable node
+	To         string // Unreachable node
+	Weight     int    // Weight of the shortest path from the source node to From
+	EdgeWeight int    // Weight of the edge
+}
+
+// Reachability is the result of a bounded search.
+type Reachability struct {
+	Nodes    map[string]ReachableNode // Nodes reachable within the budget
+	Boundary []BoundaryEdge           // Edges crossing the budget
+}
+
+// Reachable returns all the nodes within the provided graph object that can be reached from source with a path
+// of weight at most maxWeight, together with the edges crossing the budget.
+// The part of a boundary edge covered within the budget is maxWeight - Weight.
+func Reachable(graph dijkstrastructs.GraphObject, source string, maxWeight int, bannedEdges dijkstrastructs.UnusableEdgeMap) Reachability {
+	ret := Reachability{make(map[string]ReachableNode), make([]BoundaryEdge, 0)}
+	if maxWeight < 0 {
+		return ret
+	}
+	var succs []dijkstrastructs.Connection
+	crossing := make([]BoundaryEdge, 0)
+
+	openListF := &DijkstraQueue{}
+	heap.Init(openListF)
+	heap.Push(openListF, newDijkstraCandidate(source, nil, 0))
+
+	for openListF.Len() > 0 {
+		forwCandidate := heap.Pop(openListF).(*dijkstrastructs.DijkstraCandidate)
+
+		if _, ok := ret.Nodes[forwCandidate.Node]; ok {
+			continue
+		}
+		rn := ReachableNode{Weight: forwCandidate.Weight}
+		if forwCandidate.Parent != nil {
+			rn.Parent = forwCandidate.Parent.Node
+		}
+		ret.Nodes[forwCandidate.Node] = rn
+
+		succs = successorsForPath(graph, forwCandidate, bannedEdges)
+
+		// for each successors
+		for _, s := range succs {
+			if _, ok := ret.Nodes[s.Destination]; ok {
+				continue
+			}
+			if forwCandidate.Weight+s.Weight > maxWeight {
+				crossing = append(crossing, BoundaryEdge{forwCandidate.Node, s.Destination, forwCandidate.Weight, s.Weight})
+				continue
+			}
+			heap.Push(openListF, newDijkstraCandidateFromConnection(forwCandidate, s, forwCandidate.Weight+s.Weight))
+		}
+	}
+
+	// edges leading to nodes reached later by another path are not on the boundary
+	for _, e := range crossing {
+		if _, ok := ret.Nodes[e.To]; !ok {
+			ret.Boundary = append(ret.Boundary, e)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2013 Alessandro Frossi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dijkstra
+
+import (
+	"github.com/kirves/godijkstra/common/path"
+	"github.com/kirves/godijkstra/common/structs"
+)
+
+// RoutingEntry is the route from a node to the target of a reverse search.
+type RoutingEntry struct {
+	Weight  int    // Weight of the shortest path from the node to the target
+	NextHop string // Next node on the shortest path (empty for the target)
+	EdgeID  string // Identifier of the edge going to the next hop (if any)
+}
+
+// RoutingTable maps every node that can reach the target of a reverse search to its route.
+type RoutingTable struct {
+	Target  string                  // Target node of the search
+	Entries map[string]RoutingEntry // Route of each node reaching the target
+
+	candidates map[string]*dijkstrastructs.DijkstraCandidate // Backward search result, used to rebuild paths
+}
+
+// ShortestPathsTo returns the shortest paths going from every node of the provided graph object to the target node,
+// as a routing table. The search runs backwards from target, using the predecessors of each node.
+func ShortestPathsTo(graph dijkstrastructs.GraphObject, target string, bannedEdges dijkstrastructs.UnusableEdgeMap) RoutingTable {
+	ws := newWorkspace(SearchOptions{})
+	visitedNodesB, openListB := ws.backward()
+	ret := RoutingTable{target, make(map[string]RoutingEntry), visitedNodesB}
+
+	openListB.push(ws.newCandidate(target, nil, 0))
+
+	for openListB.Len() > 0 {
+		backCandidate := openListB.pop()
+		if !ws.settleBackward(graph, backCandidate, bannedEdges) {
+			continue
+		}
+		re := RoutingEntry{Weight: backCandidate.Weight}
+		if backCandidate.Parent != nil {
+			re.NextHop = backCandidate.Parent.Node
+			re.EdgeID = backCandidate.EdgeID
+		}
+		ret.Entries[backCandidate.Node] = re
+	}
+	return ret
+}
+
+// PathFrom returns the shortest path going from node to the target of the routing table.
+func (rt RoutingTable) PathFrom(node string) (dijkstrapath.DijkstraPath, bool) {
+	backCandidate, ok := rt.candidates[node]
+	if !ok {
+		return dijkstrapath.DijkstraPath{}, false
+	}
+	cs := dijkstrastructs.CandidateSolution{
+		Length:        backCandidate.Weight,
+		ForwCandidate: newDijkstraCandidate(node, nil, 0),
+		BackCandidate: backCandidate,
+	}
+	return dijkstrapath.ConvertToDijkstraPath(cs, node, rt.Target), true
+}
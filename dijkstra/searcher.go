@@ -0,0 +1,199 @@
+/*
+Copyright 2013 Alessandro Frossi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dijkstra
+
+import (
+	"github.com/kirves/godijkstra/common/path"
+	"github.com/kirves/godijkstra/common/structs"
+)
+
+// Searcher runs path searches reusing the same visited node maps, queues and candidates, so that a sequence of
+// searches allocates memory only when a search visits more nodes than any of the previous ones.
+// A Searcher must not be used by several goroutines at once; use a sync.Pool of Searchers to serve concurrent queries:
+//
+//	pool := sync.Pool{New: func() interface{} { return dijkstra.NewSearcher(dijkstra.SearchOptions{}) }}
+//	s := pool.Get().(*dijkstra.Searcher)
+//	p, ok := s.Dijkstra(graph, start, end, dijkstrastructs.EmptyUnusableEdgeMap())
+//	pool.Put(s)
+//
+// The paths returned by a Searcher do not share any memory with it.
+type Searcher struct {
+	opts       SearchOptions
+	workspaces map[int]*workspace // Workspace of each search type
+}
+
+// NewSearcher returns a Searcher running searches with the options opts.
+func NewSearcher(opts SearchOptions) *Searcher {
+	return &Searcher{opts, make(map[int]*workspace)}
+}
+
+// Dijkstra returns the shortest path within the provided graph object that goes from startNode to endNode nodes,
+// as Dijkstra does.
+func (s *Searcher) Dijkstra(graph dijkstrastructs.GraphObject, startNode, endNode string, bannedEdges dijkstrastructs.UnusableEdgeMap) (dijkstrapath.DijkstraPath, bool) {
+	return s.workspace(VANILLA).dijkstra(graph, startNode, endNode, bannedEdges)
+}
+
+// BiDirDijkstra returns the shortest path within the provided graph object that goes from startNode to endNode nodes,
+// as BiDirDijkstra does.
+func (s *Searcher) BiDirDijkstra(graph dijkstrastructs.GraphObject, startNode, endNode string, bannedEdges dijkstrastructs.UnusableEdgeMap) (dijkstrapath.DijkstraPath, bool) {
+	return s.workspace(BIDIR).biDirDijkstra(graph, startNode, endNode, bannedEdges)
+}
+
+// SearchPath returns the shortest path within the provided graph object that goes from startNode to endNode nodes,
+// as SearchPath does.
+func (s *Searcher) SearchPath(graph dijkstrastructs.GraphObject, startNode, endNode string, searchType int) (dijkstrapath.DijkstraPath, bool) {
+	return s.SearchFunc(searchType)(graph, startNode, endNode, dijkstrastructs.EmptyUnusableEdgeMap())
+}
+
+// SearchFunc returns a search function running the algorithm defined by searchType with the Searcher, as SearchFunc does.
+// The returned function shares the state of the Searcher and must not be used concurrently with it.
+func (s *Searcher) SearchFunc(searchType int) func(dijkstrastructs.GraphObject, string, string, dijkstrastructs.UnusableEdgeMap) (dijkstrapath.DijkstraPath, bool) {
+	return func(graph dijkstrastructs.GraphObject, startNode, endNode string, bannedEdges dijkstrastructs.UnusableEdgeMap) (dijkstrapath.DijkstraPath, bool) {
+		switch t := selectSearchType(graph, searchType, s.opts); t {
+		case VANILLA, DIAL:
+			return s.workspace(t).dijkstra(graph, startNode, endNode, bannedEdges)
+		case BIDIR:
+			return s.workspace(t).biDirDijkstra(graph, startNode, endNode, bannedEdges)
+		case BFS:
+			return s.workspace(t).bfs(graph, startNode, endNode, bannedEdges)
+		case BIDIR_BFS:
+			return s.workspace(t).biDirBFS(graph, startNode, endNode, bannedEdges)
+		case ZERO_ONE_BFS:
+			return s.workspace(t).zeroOneBFS(graph, startNode, endNode, bannedEdges)
+		}
+		return dijkstrapath.DijkstraPath{}, false
+	}
+}
+
+func (s *Searcher) workspace(searchType int) *workspace {
+	ws, ok := s.workspaces[searchType]
+	if !ok {
+		opts := s.opts
+		if searchType == DIAL {
+			opts.Queue = BucketQueue
+		}
+		ws = newWorkspace(opts)
+		ws.reused = true
+		s.workspaces[searchType] = ws
+	} else {
+		ws.reset()
+	}
+	return ws
+}
+
+// candidateBlockSize is the number of candidates allocated at once by a workspace reused by a Searcher.
+const candidateBlockSize = 256
+
+// workspace holds the state of a search.
+type workspace struct {
+	opts          SearchOptions
+	visitedNodesF map[string]*dijkstrastructs.DijkstraCandidate
+	visitedNodesB map[string]*dijkstrastructs.DijkstraCandidate // Created with openListB when first needed
+	minHops       map[string]int                                // Created with hopListF when first needed
+	openListF     candidateQueue
+	openListB     candidateQueue
+	hopListF      candidateQueue                       // Open list of hop-limited searches
+	frontierF     []*dijkstrastructs.DijkstraCandidate // Breadth-first search queues
+	frontierB     []*dijkstrastructs.DijkstraCandidate
+	next          []*dijkstrastructs.DijkstraCandidate
+	deque         candidateDeque // 0-1 breadth-first search queue
+	succs         []dijkstrastructs.Connection
+
+	reused bool                                  // The workspace serves several searches and allocates candidates by blocks
+	blocks [][]dijkstrastructs.DijkstraCandidate // Candidates handed out by newCandidate
+	block  int                                   // Block the next candidate is taken from
+	used   int                                   // Candidates already taken from the current block
+}
+
+func newWorkspace(opts SearchOptions) *workspace {
+	return &workspace{
+		opts:          opts,
+		visitedNodesF: make(map[string]*dijkstrastructs.DijkstraCandidate),
+		openListF:     opts.newQueue(),
+	}
+}
+
+// reset prepares the workspace for a new search, invalidating every candidate of the previous ones.
+func (ws *workspace) reset() {
+	for k := range ws.visitedNodesF {
+		delete(ws.visitedNodesF, k)
+	}
+	for k := range ws.visitedNodesB {
+		delete(ws.visitedNodesB, k)
+	}
+	for k := range ws.minHops {
+		delete(ws.minHops, k)
+	}
+	ws.openListF.reset()
+	if ws.openListB != nil {
+		ws.openListB.reset()
+	}
+	ws.deque.reset()
+	if ws.hopListF != nil {
+		ws.hopListF.reset()
+	}
+	ws.block, ws.used = 0, 0
+}
+
+// hopList returns the open list of hop-limited searches, which keep several candidates per node
+// and cannot use an indexed heap.
+func (ws *workspace) hopList() candidateQueue {
+	if ws.hopListF == nil {
+		ws.hopListF = ws.opts.newLazyQueue()
+		ws.minHops = make(map[string]int)
+	}
+	return ws.hopListF
+}
+
+// backward returns the visited nodes and the open list of backward searches.
+func (ws *workspace) backward() (map[string]*dijkstrastructs.DijkstraCandidate, candidateQueue) {
+	if ws.openListB == nil {
+		ws.visitedNodesB = make(map[string]*dijkstrastructs.DijkstraCandidate)
+		ws.openListB = ws.opts.newQueue()
+	}
+	return ws.visitedNodesB, ws.openListB
+}
+
+// newCandidate is newDijkstraCandidate, taking the candidate from the blocks of a reused workspace.
+// A workspace running a single search allocates its candidates one by one, since most of a block would be wasted.
+func (ws *workspace) newCandidate(node string, parent *dijkstrastructs.DijkstraCandidate, w int) *dijkstrastructs.DijkstraCandidate {
+	if !ws.reused {
+		return newDijkstraCandidate(node, parent, w)
+	}
+	if ws.block < len(ws.blocks) && ws.used == len(ws.blocks[ws.block]) {
+		ws.block, ws.used = ws.block+1, 0
+	}
+	if ws.block == len(ws.blocks) {
+		ws.blocks = append(ws.blocks, make([]dijkstrastructs.DijkstraCandidate, candidateBlockSize))
+	}
+	dc := &ws.blocks[ws.block][ws.used]
+	ws.used++
+	hops := 0
+	if parent != nil {
+		hops = parent.Hops + 1
+	}
+	*dc = dijkstrastructs.DijkstraCandidate{Node: node, Parent: parent, Weight: w, Hops: hops}
+	return dc
+}
+
+// newCandidateFromConnection is newDijkstraCandidateFromConnection, taking the candidate from newCandidate.
+func (ws *workspace) newCandidateFromConnection(parent *dijkstrastructs.DijkstraCandidate, c dijkstrastructs.Connection, w int) *dijkstrastructs.DijkstraCandidate {
+	dc := ws.newCandidate(c.Destination, parent, w)
+	dc.EdgeID = c.ID
+	dc.EdgeAttributes = c.Attributes
+	return dc
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2013 Alessandro Frossi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dijkstra
+
+import (
+	"fmt"
+	"github.com/kirves/godijkstra/common/structs"
+	"github.com/kirves/godijkstra/graphtest"
+	"math/rand"
+)
+
+type testGraph struct {
+	nodes        map[string]interface{}
+	edges        map[string]map[string]interface{}
+	reverseEdges map[string]map[string]interface{}
+	weights      map[string]map[string]int
+}
+
+func newTestGraph() *testGraph {
+	return &testGraph{make(map[string]interface{}), make(map[string]map[string]interface{}), make(map[string]map[string]interface{}), make(map[string]map[string]int)}
+}
+
+func (t *testGraph) addEdge(n1, n2 string, w int) {
+	t.nodes[n1] = struct{}{}
+	t.nodes[n2] = struct{}{}
+	if _, ok := t.edges[n1]; !ok {
+		t.edges[n1] = make(map[string]interface{})
+	}
+	t.edges[n1][n2] = struct{}{}
+	if _, ok := t.reverseEdges[n2]; !ok {
+		t.reverseEdges[n2] = make(map[string]interface{})
+	}
+	t.reverseEdges[n2][n1] = struct{}{}
+	if _, ok := t.weights[n1]; !ok {
+		t.weights[n1] = make(map[string]int)
+	}
+	t.weights[n1][n2] = w
+}
+
+func (t *testGraph) SuccessorsForNode(node string) []dijkstrastructs.Connection {
+	ret := make([]dijkstrastructs.Connection, len(t.edges[node]))
+	i := 0
+	for k, _ := range t.edges[node] {
+		ret[i] = dijkstrastructs.Connection{Destination: k, Weight: t.EdgeWeight(node, k)}
+		i++
+	}
+	return ret
+}
+
+func (t *testGraph) PredecessorsFromNode(node string) []dijkstrastructs.Connection {
+	ret := make([]dijkstrastructs.Connection, len(t.reverseEdges[node]))
+	i := 0
+	for k, _ := range t.reverseEdges[node] {
+		ret[i] = dijkstrastructs.Connection{Destination: k, Weight: t.EdgeWeight(k, node)}
+		i++
+	}
+	return ret
+}
+
+func (t *testGraph) EdgeWeight(n1, n2 string) int {
+	if w, ok := t.weights[n1][n2]; ok {
+		return w
+	}
+	return 1
+}
+
+// newGridTestGraph returns a size x size grid whose nodes are linked in both directions by edges of random weight between 1 and 10.
+func newGridTestGraph(size int, seed int64) *graphtest.Graph {
+	r := rand.New(rand.NewSource(seed))
+	g := graphtest.NewGraph()
+	for i := 0; i < size; i++ {
+		for j := 0; j < size; j++ {
+			if i+1 < size {
+				g.AddEdge(gridNode(i, j), gridNode(i+1, j), "", 1+r.Intn(10))
+				g.AddEdge(gridNode(i+1, j), gridNode(i, j), "", 1+r.Intn(10))
+			}
+			if j+1 < size {
+				g.AddEdge(gridNode(i, j), gridNode(i, j+1), "", 1+r.Intn(10))
+				g.AddEdge(gridNode(i, j+1), gridNode(i, j), "", 1+r.Intn(10))
+			}
+		}
+	}
+	return g
+}
+
+// newRandomTestGraph returns a graph of n nodes with m random edges of weight between 0 and maxWeight.
+func newRandomTestGraph(n, m, maxWeight int, seed int64) *graphtest.Graph {
+	r := rand.New(rand.NewSource(seed))
+	g := graphtest.NewGraph()
+	for i := 0; i < m; i++ {
+		g.AddEdge(randomNode(r.Intn(n)), randomNode(r.Intn(n)), fmt.Sprint(i), r.Intn(maxWeight+1))
+	}
+	return g
+}
+
+func randomNode(i int) string {
+	return fmt.Sprint("N", i)
+}
+
+func gridNode(i, j int) string {
+	return fmt.Sprintf("%d-%d", i, j)
+}
+
+// unweightedTestGraph declares itself unweighted, whatever the weights of its edges.
+type unweightedTestGraph struct {
+	*graphtest.Graph
+}
+
+func (t *unweightedTestGraph) IsUnweighted() bool {
+	return true
+}
+
+// skewedTestGraph returns edge weights that disagree with its connections.
+type skewedTestGraph struct {
+	*graphtest.Graph
+	skew int
+}
+
+func (t *skewedTestGraph) EdgeWeight(n1, n2 string) int {
+	return t.Graph.EdgeWeight(n1, n2) + t.skew
+}
+
+type timeDependentTestGraph struct {
+	edges map[string]map[string]dijkstrastructs.TravelTimeProfile
+}
+
+func newTimeDependentTestGraph() *timeDependentTestGraph {
+	return &timeDependentTestGraph{make(map[string]map[string]dijkstrastructs.TravelTimeProfile)}
+}
+
+func (t *timeDependentTestGraph) addEdge(n1, n2 string, p dijkstrastructs.TravelTimeProfile) {
+	if _, ok := t.edges[n1]; !ok {
+		t.edges[n1] = make(map[string]dijkstrastructs.TravelTimeProfile)
+	}
+	t.edges[n1][n2] = p
+}
+
+func (t *timeDependentTestGraph) TimeDependentSuccessorsForNode(node string) []dijkstrastructs.TimeDependentConnection {
+	ret := make([]dijkstrastructs.TimeDependentConnection, 0, len(t.edges[node]))
+	for k, p := range t.edges[node] {
+		ret = append(ret, dijkstrastructs.TimeDependentConnection{Destination: k, Profile: p})
+	}
+	return ret
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2013 Alessandro Frossi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dijkstra
+
+import (
+	"container/heap"
+	"github.com/kirves/godijkstra/common/path"
+	"github.com/kirves/godijkstra/common/structs"
+)
+
+// TimeDependentDijkstra returns the earliest arrival path within the provided time-dependent graph that goes
+// from startNode to endNode, leaving startNode at time departure.
+// The weight of each element of the returned path is the arrival time at its node, so the weight of the path is the
+// arrival time at endNode. The travel time profiles of the graph must have the FIFO property.
+func TimeDependentDijkstra(graph dijkstrastructs.TimeDependentGraph, startNode, endNode string, departure int, bannedEdges dijkstrastructs.UnusableEdgeMap) (dijkstrapath.DijkstraPath, bool) {
+	visitedNodesF := make(map[string]*dijkstrastructs.DijkstraCandidate)
+
+	openListF := &DijkstraQueue{}
+	heap.Init(openListF)
+	heap.Push(openListF, newDijkstraCandidate(startNode, nil, departure))
+
+	for openListF.Len() > 0 {
+		forwCandidate := heap.Pop(openListF).(*dijkstrastructs.DijkstraCandidate)
+
+		// check if we reached termination
+		if forwCandidate.Node == endNode {
+			cs := dijkstrastructs.CandidateSolution{
+				Length:        forwCandidate.Weight,
+				ForwCandidate: forwCandidate,
+				BackCandidate: &dijkstrastructs.DijkstraCandidate{Node: endNode},
+			}
+			return dijkstrapath.ConvertToDijkstraPath(cs, startNode, endNode), true
+		}
+
+		if _, ok := visitedNodesF[forwCandidate.Node]; ok {
+			continue
+		} else {
+			visitedNodesF[forwCandidate.Node] = forwCandidate
+		}
+
+		for _, s := range graph.TimeDependentSuccessorsForNode(forwCandidate.Node) {
+			if bannedEdges.IsBanned(forwCandidate.Node, s.Destination, "") {
+				continue
+			}
+			if _, ok := visitedNodesF[s.Destination]; ok {
+				continue
+			}
+			newPath := newDijkstraCandidate(s.Destination, forwCandidate, s.Profile.ArrivalTime(forwCandidate.Weight))
+			heap.Push(openListF, newPath)
+		}
+	}
+	return dijkstrapath.DijkstraPath{}, false
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2013 Alessandro Frossi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dijkstra
+
+import (
+	"github.com/kirves/godijkstra/common/structs"
+	"testing"
+)
+
+var (
+	tdGraph *timeDependentTestGraph
+)
+
+func init() {
+	// the highway S -> H -> T is fast, except during the rush hour around time 100
+	rushHour := dijkstrastructs.TravelTimeProfile{{Time: 60, Duration: 10}, {Time: 100, Duration: 50}, {Time: 140, Duration: 10}}
+	tdGraph = newTimeDependentTestGraph()
+	tdGraph.addEdge("S", "H", dijkstrastructs.ConstantProfile(5))
+	tdGraph.addEdge("H", "T", rushHour)
+	tdGraph.addEdge("S", "A", dijkstrastructs.ConstantProfile(10))
+	tdGraph.addEdge("A", "B", dijkstrastructs.ConstantProfile(10))
+	tdGraph.addEdge("B", "T", dijkstrastructs.ConstantProfile(10))
+}
+
+func TestProfile(t *testing.T) {
+	p := dijkstrastructs.TravelTimeProfile{{Time: 60, Duration: 10}, {Time: 100, Duration: 50}, {Time: 140, Duration: 10}}
+	times := map[int]int{0: 10, 60: 10, 80: 30, 100: 50, 120: 30, 200: 10}
+	for departure, exp := range times {
+		if tt := p.TravelTime(departure); tt != exp {
+			t.Fatalf("Wrong travel time at %d:\nExpected: %d\nGot: %d\n", departure, exp, tt)
+		}
+	}
+	if !p.IsFIFO() {
+		t.Fatal("FIFO profile reported as non-FIFO.")
+	}
+	if (dijkstrastructs.TravelTimeProfile{{Time: 0, Duration: 50}, {Time: 10, Duration: 10}}).IsFIFO() {
+		t.Fatal("Non-FIFO profile reported as FIFO.")
+	}
+}
+
+func TestTimeDependent(t *testing.T) {
+	cases := []struct {
+		departure int
+		expPath   []string
+		arrival   int
+	}{
+		{0, []string{"S", "H", "T"}, 15},
+		{95, []string{"S", "A", "B", "T"}, 125},
+		{200, []string{"S", "H", "T"}, 215},
+	}
+	for _, c := range cases {
+		path, valid := TimeDependentDijkstra(tdGraph, "S", "T", c.departure, dijkstrastructs.EmptyUnusableEdgeMap())
+		if !valid {
+			t.Fatal("Validity error.")
+		}
+		if len(path.Path) != len(c.expPath) {
+			t.Fatalf("Wrong path: %v", path.Path)
+		}
+		for i, v := range path.Path {
+			if v.Node != c.expPath[i] {
+				t.Fatalf("Wrong path: %v", path.Path)
+			}
+		}
+		if path.Path[0].Weight != c.departure {
+			t.Fatalf("Wrong departure time:\nExpected: %d\nGot: %d\n", c.departure, path.Path[0].Weight)
+		}
+		if path.Weight != c.arrival {
+			t.Fatalf("Wrong arrival time:\nExpected: %d\nGot: %d\n", c.arrival, path.Weight)
+		}
+	}
+}
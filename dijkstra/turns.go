@@ -0,0 +1,84 @@
+/*
+Copyright 2013 Alessandro Frossi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dijkstra
+
+import (
+	"container/heap"
+	"github.com/kirves/godijkstra/common/path"
+	"github.com/kirves/godijkstra/common/structs"
+)
+
+// edgeState is a state of the edge-based search: the edge going from node from to node to.
+type edgeState struct {
+	from, to string
+}
+
+// TurnAwareDijkstra returns the shortest path within the provided graph object that goes from startNode to endNode
+// nodes, respecting the forbidden turns and adding the turn costs defined by turns.
+// The search runs on the edges of the graph rather than on its nodes, so that the returned path may go through the
+// same node more than once when a detour is required to avoid a forbidden turn.
+// The weight of each element of the path includes the cost of the turn taken to reach it.
+func TurnAwareDijkstra(graph dijkstrastructs.GraphObject, turns dijkstrastructs.TurnCostModel, startNode, endNode string, bannedEdges dijkstrastructs.UnusableEdgeMap) (dijkstrapath.DijkstraPath, bool) {
+	var succs []dijkstrastructs.Connection
+	visitedEdges := make(map[edgeState]bool)
+
+	openListF := &DijkstraQueue{}
+	heap.Init(openListF)
+	heap.Push(openListF, newDijkstraCandidate(startNode, nil, 0))
+
+	for openListF.Len() > 0 {
+		forwCandidate := heap.Pop(openListF).(*dijkstrastructs.DijkstraCandidate)
+
+		// check if we reached termination
+		if forwCandidate.Node == endNode {
+			cs := dijkstrastructs.CandidateSolution{
+				Length:        forwCandidate.Weight,
+				ForwCandidate: forwCandidate,
+				BackCandidate: &dijkstrastructs.DijkstraCandidate{Node: endNode},
+			}
+			return dijkstrapath.ConvertToDijkstraPath(cs, startNode, endNode), true
+		}
+
+		state := edgeState{to: forwCandidate.Node}
+		if forwCandidate.Parent != nil {
+			state.from = forwCandidate.Parent.Node
+		}
+		if visitedEdges[state] {
+			continue
+		}
+		visitedEdges[state] = true
+
+		succs = successorsForPath(graph, forwCandidate, bannedEdges)
+
+		// for each successors
+		for _, s := range succs {
+			if visitedEdges[edgeState{forwCandidate.Node, s.Destination}] {
+				continue
+			}
+			w := forwCandidate.Weight + s.Weight
+			if forwCandidate.Parent != nil {
+				cost, allowed := turns.TurnCost(forwCandidate.Parent.Node, forwCandidate.Node, s.Destination)
+				if !allowed {
+					continue
+				}
+				w += cost
+			}
+			heap.Push(openListF, newDijkstraCandidateFromConnection(forwCandidate, s, w))
+		}
+	}
+	return dijkstrapath.DijkstraPath{}, false
+}
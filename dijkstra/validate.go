@@ -0,0 +1,228 @@
+/*
+Copyright 2013 Alessandro Frossi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dijkstra
+
+import (
+	"fmt"
+	"github.com/kirves/godijkstra/common/structs"
+	"sort"
+)
+
+// IssueKind is the kind of inconsistency found by Validate.
+type IssueKind int
+
+const (
+	NegativeWeight     IssueKind = iota // An edge has a negative weight
+	MissingPredecessor                  // An edge is listed by SuccessorsForNode but not by PredecessorsFromNode
+	MissingSuccessor                    // An edge is listed by PredecessorsFromNode but not by SuccessorsForNode
+	WeightMismatch                      // SuccessorsForNode and PredecessorsFromNode disagree on the weight of an edge
+	EdgeWeightMismatch                  // EdgeWeight disagrees with the weight of the connections between two nodes
+)
+
+var issueKindNames = map[IssueKind]string{
+	NegativeWeight:     "negative weight",
+	MissingPredecessor: "missing predecessor",
+	MissingSuccessor:   "missing successor",
+	WeightMismatch:     "weight mismatch",
+	EdgeWeightMismatch: "edge weight mismatch",
+}
+
+func (k IssueKind) String() string {
+	return issueKindNames[k]
+}
+
+// ValidationIssue is an inconsistency found on the edge going from node From to node To.
+type ValidationIssue struct {
+	Kind     IssueKind // Kind of inconsistency
+	From     string    // Source node of the edge
+	To       string    // Destination node of the edge
+	ID       string    // Edge identifier (if any)
+	Weight   int       // Weight of the edge, as listed by SuccessorsForNode (or PredecessorsFromNode if missing)
+	Expected int       // Conflicting weight, for WeightMismatch and EdgeWeightMismatch issues
+}
+
+func (i ValidationIssue) String() string {
+	switch i.Kind {
+	case WeightMismatch, EdgeWeightMismatch:
+		return fmt.Sprintf("%s on edge %s -> %s: %d != %d", i.Kind, i.From, i.To, i.Weight, i.Expected)
+	default:
+		return fmt.Sprintf("%s on edge %s -> %s (weight %d)", i.Kind, i.From, i.To, i.Weight)
+	}
+}
+
+// ValidationReport lists the inconsistencies found by Validate.
+type ValidationReport struct {
+	Issues []ValidationIssue
+}
+
+// Valid states if no inconsistency was found.
+func (r ValidationReport) Valid() bool {
+	return len(r.Issues) == 0
+}
+
+// Validate checks the edges going from and to the given nodes of the provided graph object for inconsistencies
+// that would make the searches return wrong results: negative weights, edges listed only by one of SuccessorsForNode
+// and PredecessorsFromNode or with different weights, and values of EdgeWeight that do not match the weight of
+// the connections between two nodes (the lightest one, for parallel edges).
+func Validate(graph dijkstrastructs.GraphObject, nodes []string) ValidationReport {
+	ret := ValidationReport{make([]ValidationIssue, 0)}
+	for _, n := range nodes {
+		succs := graph.SuccessorsForNode(n)
+		lightest := make(map[string]int)
+		for _, s := range succs {
+			if s.Weight < 0 {
+				ret.Issues = append(ret.Issues, ValidationIssue{NegativeWeight, n, s.Destination, s.ID, s.Weight, 0})
+			}
+			if w, ok := lightest[s.Destination]; !ok || s.Weight < w {
+				lightest[s.Destination] = s.Weight
+			}
+		}
+		for _, e := range groupConnections(succs) {
+			// parallel edges can share the same identifier, so the weights listed on both sides are compared as multisets
+			onlySucc, onlyPred := diffWeights(e.weights, connectionWeights(graph.PredecessorsFromNode(e.node), n, e.id))
+			for i, w := range onlySucc {
+				if i < len(onlyPred) {
+					ret.Issues = append(ret.Issues, ValidationIssue{WeightMismatch, n, e.node, e.id, w, onlyPred[i]})
+				} else {
+					ret.Issues = append(ret.Issues, ValidationIssue{MissingPredecessor, n, e.node, e.id, w, 0})
+				}
+			}
+		}
+		for _, s := range succs {
+			w, ok := lightest[s.Destination]
+			if !ok {
+				// already checked
+				continue
+			}
+			delete(lightest, s.Destination)
+			if ew := graph.EdgeWeight(n, s.Destination); ew != w {
+				ret.Issues = append(ret.Issues, ValidationIssue{EdgeWeightMismatch, n, s.Destination, "", w, ew})
+			}
+		}
+
+		for _, e := range groupConnections(graph.PredecessorsFromNode(n)) {
+			// weight mismatches were reported along with the successors
+			onlyPred, onlySucc := diffWeights(e.weights, connectionWeights(graph.SuccessorsForNode(e.node), n, e.id))
+			for i := len(onlySucc); i < len(onlyPred); i++ {
+				if onlyPred[i] < 0 {
+					ret.Issues = append(ret.Issues, ValidationIssue{NegativeWeight, e.node, n, e.id, onlyPred[i], 0})
+				}
+				ret.Issues = append(ret.Issues, ValidationIssue{MissingSuccessor, e.node, n, e.id, onlyPred[i], 0})
+			}
+		}
+	}
+	return ret
+}
+
+// edgeGroup holds the weights of the connections to the same node with the same identifier.
+type edgeGroup struct {
+	node    string
+	id      string
+	weights []int
+}
+
+// groupConnections groups conns by destination and identifier, in order of first appearance.
+func groupConnections(conns []dijkstrastructs.Connection) []edgeGroup {
+	ret := make([]edgeGroup, 0, len(conns))
+	index := make(map[[2]string]int)
+	for _, c := range conns {
+		key := [2]string{c.Destination, c.ID}
+		i, ok := index[key]
+		if !ok {
+			i = len(ret)
+			index[key] = i
+			ret = append(ret, edgeGroup{node: c.Destination, id: c.ID})
+		}
+		ret[i].weights = append(ret[i].weights, c.Weight)
+	}
+	return ret
+}
+
+// connectionWeights returns the weights of the connections in conns going to node with the given identifier.
+func connectionWeights(conns []dijkstrastructs.Connection, node, id string) []int {
+	ret := make([]int, 0)
+	for _, c := range conns {
+		if c.Destination == node && c.ID == id {
+			ret = append(ret, c.Weight)
+		}
+	}
+	return ret
+}
+
+// diffWeights returns the sorted weights of a missing from b and of b missing from a, counting repeated weights.
+func diffWeights(a, b []int) ([]int, []int) {
+	a, b = append([]int(nil), a...), append([]int(nil), b...)
+	sort.Ints(a)
+	sort.Ints(b)
+	onlyA, onlyB := make([]int, 0), make([]int, 0)
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			onlyA = append(onlyA, a[i])
+			i++
+		case a[i] > b[j]:
+			onlyB = append(onlyB, b[j])
+			j++
+		default:
+			i++
+			j++
+		}
+	}
+	return append(onlyA, a[i:]...), append(onlyB, b[j:]...)
+}
+
+// NegativeWeightError is the value of the panic raised by a search run in debug mode
+// when it finds an edge with a negative weight.
+type NegativeWeightError struct {
+	From   string // Source node of the edge
+	To     string // Destination node of the edge
+	Weight int    // Weight of the edge
+}
+
+func (e *NegativeWeightError) Error() string {
+	return fmt.Sprintf("dijkstra: negative weight %d on edge %s -> %s", e.Weight, e.From, e.To)
+}
+
+// checkedGraph is a GraphObject panicking with a *NegativeWeightError as soon as it returns a negative weight.
+type checkedGraph struct {
+	graph dijkstrastructs.GraphObject
+}
+
+func (cg *checkedGraph) SuccessorsForNode(node string) []dijkstrastructs.Connection {
+	ret := cg.graph.SuccessorsForNode(node)
+	for _, s := range ret {
+		if s.Weight < 0 {
+			panic(&NegativeWeightError{node, s.Destination, s.Weight})
+		}
+	}
+	return ret
+}
+
+func (cg *checkedGraph) PredecessorsFromNode(node string) []dijkstrastructs.Connection {
+	ret := cg.graph.PredecessorsFromNode(node)
+	for _, p := range ret {
+		if p.Weight < 0 {
+			panic(&NegativeWeightError{p.Destination, node, p.Weight})
+		}
+	}
+	return ret
+}
+
+func (cg *checkedGraph) EdgeWeight(n1, n2 string) int {
+	return cg.graph.EdgeWeight(n1, n2)
+}
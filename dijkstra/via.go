@@ -0,0 +1,79 @@
+/*
+Copyright 2013 Alessandro Frossi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dijkstra
+
+import (
+	"github.com/kirves/godijkstra/common/path"
+	"github.com/kirves/godijkstra/common/structs"
+	"sync"
+)
+
+// RouteOptions defines the parameters of a RouteVia search.
+type RouteOptions struct {
+	SearchType int  // Algorithm used to search each leg (see SearchPath)
+	Parallel   bool // Search the legs concurrently (the graph object must be safe for concurrent use)
+}
+
+// Route is a path going through an ordered sequence of waypoints.
+type Route struct {
+	Path    dijkstrapath.DijkstraPath // Complete path, with cumulative weights
+	LegEnds []int                     // Index in Path.Path of the last element of each leg
+}
+
+// RouteVia returns the shortest path within the provided graph object that starts from the first waypoint,
+// visits the following ones in order and ends in the last one.
+// Each leg between two consecutive waypoints is searched on its own and the legs are merged into a single path.
+func RouteVia(graph dijkstrastructs.GraphObject, waypoints []string, opts RouteOptions) (Route, bool) {
+	if len(waypoints) < 2 {
+		return Route{}, false
+	}
+
+	legs := make([]dijkstrapath.DijkstraPath, len(waypoints)-1)
+	valid := make([]bool, len(legs))
+	if opts.Parallel {
+		var wg sync.WaitGroup
+		for i := range legs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				legs[i], valid[i] = SearchPath(graph, waypoints[i], waypoints[i+1], opts.SearchType)
+			}(i)
+		}
+		wg.Wait()
+	} else {
+		for i := range legs {
+			legs[i], valid[i] = SearchPath(graph, waypoints[i], waypoints[i+1], opts.SearchType)
+			if !valid[i] {
+				return Route{}, false
+			}
+		}
+	}
+
+	ret := Route{legs[0], make([]int, 0, len(legs))}
+	for i, leg := range legs {
+		if !valid[i] {
+			return Route{}, false
+		}
+		if i > 0 {
+			ret.Path = ret.Path.MergeWith(leg)
+		}
+		ret.LegEnds = append(ret.LegEnds, len(ret.Path.Path)-1)
+	}
+	ret.Path.StartNode = waypoints[0]
+	ret.Path.EndNode = waypoints[len(waypoints)-1]
+	return ret, true
+}
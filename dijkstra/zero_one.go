@@ -0,0 +1,134 @@
+/*
+Copyright 2013 Alessandro Frossi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dijkstra
+
+import (
+	"github.com/kirves/godijkstra/common/path"
+	"github.com/kirves/godijkstra/common/structs"
+)
+
+// ZeroOneBFS returns the shortest path within the provided graph object that goes from startNode to endNode nodes,
+// in a graph whose edge weights are either 0 or 1. The search is a breadth-first search over a double-ended queue,
+// where candidates reached through edges of weight 0 are put in front, running in linear time.
+// If the search meets an edge whose weight is neither 0 nor 1, it starts over with the Dijkstra algorithm,
+// so that the shortest path is returned on any graph.
+func ZeroOneBFS(graph dijkstrastructs.GraphObject, startNode, endNode string, bannedEdges dijkstrastructs.UnusableEdgeMap) (dijkstrapath.DijkstraPath, bool) {
+	return newWorkspace(SearchOptions{}).zeroOneBFS(graph, startNode, endNode, bannedEdges)
+}
+
+// zeroOneBFS does not support a hop limit: if opts.MaxHops is set the vanilla algorithm is used instead.
+func (ws *workspace) zeroOneBFS(graph dijkstrastructs.GraphObject, startNode, endNode string, bannedEdges dijkstrastructs.UnusableEdgeMap) (dijkstrapath.DijkstraPath, bool) {
+	if ws.opts.MaxHops > 0 {
+		return ws.dijkstra(graph, startNode, endNode, bannedEdges)
+	}
+	g := ws.opts.graph(graph)
+	visitedNodesF := ws.visitedNodesF
+	openListF := &ws.deque
+	openListF.pushBack(ws.newCandidate(startNode, nil, 0))
+
+	for openListF.Len() > 0 {
+		forwCandidate := openListF.popFront()
+
+		// check if we reached termination
+		if forwCandidate.Node == endNode {
+			cs := dijkstrastructs.CandidateSolution{
+				Length:        forwCandidate.Weight,
+				ForwCandidate: forwCandidate,
+				BackCandidate: &dijkstrastructs.DijkstraCandidate{Node: endNode},
+			}
+			return dijkstrapath.ConvertToDijkstraPath(cs, startNode, endNode), true
+		}
+
+		if _, ok := visitedNodesF[forwCandidate.Node]; ok {
+			continue
+		}
+		visitedNodesF[forwCandidate.Node] = forwCandidate
+
+		ws.succs = appendSuccessors(ws.succs[:0], g, forwCandidate, bannedEdges)
+		for _, s := range ws.succs {
+			if s.Weight != 0 && s.Weight != 1 {
+				ws.reset()
+				return ws.dijkstra(graph, startNode, endNode, bannedEdges)
+			}
+			if _, ok := visitedNodesF[s.Destination]; ok {
+				continue
+			}
+			newPath := ws.newCandidateFromConnection(forwCandidate, s, forwCandidate.Weight+s.Weight)
+			if s.Weight == 0 {
+				openListF.pushFront(newPath)
+			} else {
+				openListF.pushBack(newPath)
+			}
+		}
+	}
+	return dijkstrapath.DijkstraPath{}, false
+}
+
+// candidateDeque is a double-ended queue of candidates backed by a circular buffer.
+type candidateDeque struct {
+	buf  []*dijkstrastructs.DijkstraCandidate
+	head int
+	n    int
+}
+
+func (d *candidateDeque) Len() int {
+	return d.n
+}
+
+func (d *candidateDeque) pushFront(c *dijkstrastructs.DijkstraCandidate) {
+	d.grow()
+	d.head = (d.head - 1 + len(d.buf)) % len(d.buf)
+	d.buf[d.head] = c
+	d.n++
+}
+
+func (d *candidateDeque) pushBack(c *dijkstrastructs.DijkstraCandidate) {
+	d.grow()
+	d.buf[(d.head+d.n)%len(d.buf)] = c
+	d.n++
+}
+
+func (d *candidateDeque) popFront() *dijkstrastructs.DijkstraCandidate {
+	c := d.buf[d.head]
+	d.buf[d.head] = nil
+	d.head = (d.head + 1) % len(d.buf)
+	d.n--
+	return c
+}
+
+func (d *candidateDeque) reset() {
+	for d.n > 0 {
+		d.popFront()
+	}
+	d.head = 0
+}
+
+// grow doubles the buffer if it is full.
+func (d *candidateDeque) grow() {
+	if d.n < len(d.buf) {
+		return
+	}
+	size := 2 * len(d.buf)
+	if size == 0 {
+		size = 16
+	}
+	buf := make([]*dijkstrastructs.DijkstraCandidate, size)
+	for i := 0; i < d.n; i++ {
+		buf[i] = d.buf[(d.head+i)%len(d.buf)]
+	}
+	d.buf, d.head = buf, 0
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2013 Alessandro Frossi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generate
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ReadDIMACS reads a graph in the DIMACS shortest path format, as used to distribute road networks:
+// a problem line "p sp <nodes> <edges>" followed by one line "a <from> <to> <weight>" per edge; lines starting
+// with "c" are comments. DIMACS nodes are numbered from 1, so DIMACS node i is named after index i-1.
+// Negative weights and a number of edges other than the one of the problem line are rejected.
+func ReadDIMACS(r io.Reader) (*Graph, error) {
+	var b *builder
+	edges := 0
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || fields[0] == "c" {
+			continue
+		}
+		switch fields[0] {
+		case "p":
+			if b != nil || len(fields) != 4 || fields[1] != "sp" {
+				return nil, fmt.Errorf("line %d: invalid problem line", line)
+			}
+			n, err1 := strconv.Atoi(fields[2])
+			m, err2 := strconv.Atoi(fields[3])
+			if err1 != nil || err2 != nil || n < 0 || m < 0 {
+				return nil, fmt.Errorf("line %d: invalid problem size", line)
+			}
+			b, edges = newBuilder(n, m), m
+		case "a":
+			if b == nil {
+				return nil, fmt.Errorf("line %d: edge before the problem line", line)
+			}
+			if len(fields) != 4 {
+				return nil, fmt.Errorf("line %d: invalid edge", line)
+			}
+			u, err1 := strconv.Atoi(fields[1])
+			v, err2 := strconv.Atoi(fields[2])
+			w, err3 := strconv.Atoi(fields[3])
+			if err1 != nil || err2 != nil || err3 != nil || u < 1 || v < 1 || u > b.n || v > b.n {
+				return nil, fmt.Errorf("line %d: invalid edge", line)
+			}
+			if w < 0 {
+				return nil, fmt.Errorf("line %d: negative edge weight %d", line, w)
+			}
+			b.addEdge(u-1, v-1, w)
+		default:
+			return nil, fmt.Errorf("line %d: unknown line type %q", line, fields[0])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return nil, fmt.Errorf("missing problem line")
+	}
+	if len(b.from) != edges {
+		return nil, fmt.Errorf("found %d edges, the problem line declares %d", len(b.from), edges)
+	}
+	return b.build(), nil
+}
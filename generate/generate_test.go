@@ -0,0 +1,253 @@
+/*
+Copyright 2013 Alessandro Frossi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generate
+
+import (
+	"github.com/kirves/godijkstra/common/structs"
+	"github.com/kirves/godijkstra/dijkstra"
+	"math"
+	"strings"
+	"testing"
+)
+
+// checkGraph verifies that successors and predecessors of g agree and that g is reproducible by build.
+func checkGraph(t *testing.T, name string, g *Graph, build func() *Graph) {
+	if r := dijkstra.Validate(g, g.Nodes()); !r.Valid() {
+		t.Fatalf("Inconsistent %s graph: %v", name, r.Issues[0])
+	}
+	h := build()
+	if h.NodeCount() != g.NodeCount() || h.EdgeCount() != g.EdgeCount() {
+		t.Fatalf("Non reproducible %s graph", name)
+	}
+	for i := range g.targets {
+		if g.targets[i] != h.targets[i] || g.weights[i] != h.weights[i] {
+			t.Fatalf("Non reproducible %s graph", name)
+		}
+	}
+	for _, n := range g.Nodes() {
+		for _, c := range g.SuccessorsForNode(n) {
+			if c.Destination == n {
+				t.Fatalf("Self-loop in %s graph: %s", name, n)
+			}
+		}
+	}
+}
+
+func checkWeights(t *testing.T, name string, g *Graph, minWeight, maxWeight int) {
+	for _, w := range g.weights {
+		if int(w) < minWeight || int(w) > maxWeight {
+			t.Fatalf("Weight %d out of range in %s graph", w, name)
+		}
+	}
+}
+
+func TestGrid(t *testing.T) {
+	build := func() *Graph { return Grid(5, 7, 1, 10, 1) }
+	g := build()
+	checkGraph(t, "grid", g, build)
+	checkWeights(t, "grid", g, 1, 10)
+	if g.NodeCount() != 35 || g.EdgeCount() != 2*(2*5*7-5-7) {
+		t.Fatalf("Wrong grid size: %d nodes, %d edges", g.NodeCount(), g.EdgeCount())
+	}
+	if x, y, ok := g.Coordinates(g.Node(2*7 + 3)); !ok || x != 3 || y != 2 {
+		t.Fatalf("Wrong coordinates: %v, %v", x, y)
+	}
+	if _, ok := g.Index("35"); ok {
+		t.Fatal("Index found for a missing node.")
+	}
+	if _, ok := g.Index("07"); ok {
+		t.Fatal("Index found for a node with a wrong name.")
+	}
+	if w := g.EdgeWeight("0", "1"); w < 1 || w > 10 {
+		t.Fatalf("Wrong edge weight: %d", w)
+	}
+	if w := g.EdgeWeight("0", "8"); w != -1 {
+		t.Fatalf("Wrong weight for a missing edge: %d", w)
+	}
+}
+
+func TestGeometric(t *testing.T) {
+	// 1/0.3 is not an integer: cells must still be at least as large as the radius
+	for _, radius := range []float64{0.1, 0.3} {
+		build := func() *Graph { return Geometric(300, radius, 2) }
+		g := build()
+		checkGraph(t, "geometric", g, build)
+		expEdges := 0
+		for i := 0; i < 300; i++ {
+			for j := 0; j < 300; j++ {
+				if i != j && math.Hypot(g.x[i]-g.x[j], g.y[i]-g.y[j]) < radius {
+					expEdges++
+				}
+			}
+		}
+		if g.EdgeCount() != expEdges {
+			t.Fatalf("Wrong number of edges (radius %v):\nExpected: %d\nGot: %d\n", radius, expEdges, g.EdgeCount())
+		}
+		for _, n := range g.Nodes() {
+			x1, y1, _ := g.Coordinates(n)
+			for _, c := range g.SuccessorsForNode(n) {
+				x2, y2, _ := g.Coordinates(c.Destination)
+				if c.Weight != distanceWeight(math.Hypot(x1-x2, y1-y2), 1) {
+					t.Fatalf("Wrong edge weight %s -> %s: %d", n, c.Destination, c.Weight)
+				}
+			}
+		}
+	}
+}
+
+func TestBarabasiAlbert(t *testing.T) {
+	build := func() *Graph { return BarabasiAlbert(200, 3, 1, 5, 3) }
+	g := build()
+	checkGraph(t, "Barabasi-Albert", g, build)
+	checkWeights(t, "Barabasi-Albert", g, 1, 5)
+	if g.EdgeCount() != 2*(0+1+2+3*(200-3)) {
+		t.Fatalf("Wrong number of edges: %d", g.EdgeCount())
+	}
+}
+
+func TestWattsStrogatz(t *testing.T) {
+	g := WattsStrogatz(100, 4, 0, 1, 1, 4)
+	if g.EdgeCount() != 2*100*2 {
+		t.Fatalf("Wrong number of edges in a ring lattice: %d", g.EdgeCount())
+	}
+	if g.EdgeWeight("99", "1") != 1 || g.EdgeWeight("0", "3") != -1 {
+		t.Fatal("Wrong ring lattice.")
+	}
+	build := func() *Graph { return WattsStrogatz(100, 4, 0.3, 1, 5, 4) }
+	checkGraph(t, "Watts-Strogatz", build(), build)
+}
+
+func TestComplete(t *testing.T) {
+	build := func() *Graph { return Complete(12, 3, 3, 5) }
+	g := build()
+	checkGraph(t, "complete", g, build)
+	checkWeights(t, "complete", g, 3, 3)
+	if g.EdgeCount() != 12*11 {
+		t.Fatalf("Wrong number of edges: %d", g.EdgeCount())
+	}
+}
+
+func TestErdosRenyi(t *testing.T) {
+	build := func() *Graph { return ErdosRenyi(60, 0.2, 1, 10, 3) }
+	g := build()
+	checkGraph(t, "Erdős–Rényi", g, build)
+	checkWeights(t, "Erdős–Rényi", g, 1, 10)
+	if g.NodeCount() != 60 || g.EdgeCount() < 500 || g.EdgeCount() > 900 {
+		t.Fatalf("Wrong Erdős–Rényi graph size: %d nodes, %d edges", g.NodeCount(), g.EdgeCount())
+	}
+	if g = ErdosRenyi(10, 1, 1, 10, 3); g.EdgeCount() != 90 {
+		t.Fatalf("Wrong complete Erdős–Rényi graph size: %d edges", g.EdgeCount())
+	}
+}
+
+func TestDAG(t *testing.T) {
+	build := func() *Graph { return DAG(100, 3, 1, 9, 6) }
+	g := build()
+	checkGraph(t, "DAG", g, build)
+	for _, n := range g.Nodes() {
+		i, _ := g.Index(n)
+		for _, c := range g.SuccessorsForNode(n) {
+			if j, _ := g.Index(c.Destination); j <= i {
+				t.Fatalf("Edge against the topological order: %s -> %s", n, c.Destination)
+			}
+		}
+	}
+	if len(g.SuccessorsForNode("0")) != 3 || len(g.SuccessorsForNode("98")) != 1 {
+		t.Fatal("Wrong node degrees.")
+	}
+}
+
+func TestRoadLike(t *testing.T) {
+	build := func() *Graph { return RoadLike(20, 30, 7) }
+	g := build()
+	checkGraph(t, "road-like", g, build)
+	r := dijkstra.Reachable(g, "0", math.MaxInt32, dijkstrastructs.EmptyUnusableEdgeMap())
+	if len(r.Nodes) != g.NodeCount() {
+		t.Fatalf("Disconnected road-like graph: %d nodes reachable out of %d", len(r.Nodes), g.NodeCount())
+	}
+}
+
+func TestCacheConnections(t *testing.T) {
+	g := Grid(5, 7, 1, 10, 1)
+	succs, preds := make([][]dijkstrastructs.Connection, g.NodeCount()), make([][]dijkstrastructs.Connection, g.NodeCount())
+	for i, n := range g.Nodes() {
+		succs[i], preds[i] = g.SuccessorsForNode(n), g.PredecessorsFromNode(n)
+	}
+	g.CacheConnections()
+	for i, n := range g.Nodes() {
+		for _, c := range [][2][]dijkstrastructs.Connection{{succs[i], g.SuccessorsForNode(n)}, {preds[i], g.PredecessorsFromNode(n)}} {
+			if len(c[0]) != len(c[1]) {
+				t.Fatalf("Wrong cached connections for %s: %v", n, c[1])
+			}
+			for j := range c[0] {
+				if c[0][j].Destination != c[1][j].Destination || c[0][j].Weight != c[1][j].Weight {
+					t.Fatalf("Wrong cached connections for %s: %v", n, c[1])
+				}
+			}
+		}
+	}
+	if allocs := testing.AllocsPerRun(10, func() { g.SuccessorsForNode("8"); g.PredecessorsFromNode("8") }); allocs != 0 {
+		t.Fatalf("Cached connections allocated memory: %v allocs", allocs)
+	}
+}
+
+func TestReadDIMACS(t *testing.T) {
+	in := `c a small road network
+p sp 3 3
+a 1 2 7
+a 2 3 2
+c the way back
+a 3 1 4
+`
+	g, err := ReadDIMACS(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.NodeCount() != 3 || g.EdgeCount() != 3 || g.EdgeWeight("0", "1") != 7 || g.EdgeWeight("2", "0") != 4 {
+		t.Fatalf("Wrong graph: %d nodes, %d edges", g.NodeCount(), g.EdgeCount())
+	}
+	if r := dijkstra.Validate(g, g.Nodes()); !r.Valid() {
+		t.Fatalf("Inconsistent graph: %v", r.Issues[0])
+	}
+
+	for _, in := range []string{
+		"a 1 2 3\n",
+		"p sp 2 1\na 1 3 1\n",
+		"p sp 2 1\nx\n",
+		"c empty\n",
+		"p sp 2 1\na 1 2 -4\n",
+		"p sp 2 2\na 1 2 4\n",
+		"p sp 2 1\na 1 2 4\na 2 1 4\n",
+	} {
+		if _, err := ReadDIMACS(strings.NewReader(in)); err == nil {
+			t.Fatalf("Invalid input accepted: %q", in)
+		}
+	}
+}
+
+func TestLarge(t *testing.T) {
+	if testing.Short() {
+		t.Skip("large graphs skipped in short mode")
+	}
+	g := Grid(1000, 1000, 1, 100, 8)
+	if g.NodeCount() != 1000000 || g.EdgeCount() != 2*(2*1000*1000-2000) {
+		t.Fatalf("Wrong grid size: %d nodes, %d edges", g.NodeCount(), g.EdgeCount())
+	}
+	if _, valid := dijkstra.DialDijkstra(g, "0", "5005", dijkstrastructs.EmptyUnusableEdgeMap()); !valid {
+		t.Fatal("Path not found.")
+	}
+}
@@ -0,0 +1,280 @@
+/*
+Copyright 2013 Alessandro Frossi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generate
+
+import (
+	"math"
+	"math/rand"
+)
+
+const (
+	DistanceScale   = 1000 // Weight of an edge of unit length in geometric and road-like graphs
+	ArterialSpacing = 8    // Distance in rows and columns between two arterial roads of road-like graphs
+)
+
+// Grid returns a rows x cols lattice whose adjacent nodes are linked in both directions by edges of independent
+// random weight between minWeight and maxWeight. Node i*cols + j lies in row i and column j, at coordinates (j, i).
+func Grid(rows, cols, minWeight, maxWeight int, seed int64) *Graph {
+	r := rand.New(rand.NewSource(seed))
+	b := newBuilder(rows*cols, 4*rows*cols)
+	b.x, b.y = make([]float64, rows*cols), make([]float64, rows*cols)
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			u := i*cols + j
+			b.x[u], b.y[u] = float64(j), float64(i)
+			if j+1 < cols {
+				b.addLink(u, u+1, randomWeight(r, minWeight, maxWeight), randomWeight(r, minWeight, maxWeight))
+			}
+			if i+1 < rows {
+				b.addLink(u, u+cols, randomWeight(r, minWeight, maxWeight), randomWeight(r, minWeight, maxWeight))
+			}
+		}
+	}
+	return b.build()
+}
+
+// Geometric returns a random geometric graph: n nodes placed uniformly in the unit square, where every two nodes
+// closer than radius are linked in both directions by edges weighing their distance times DistanceScale (at least 1).
+func Geometric(n int, radius float64, seed int64) *Graph {
+	r := rand.New(rand.NewSource(seed))
+	b := newBuilder(n, 0)
+	b.x, b.y = make([]float64, n), make([]float64, n)
+	for i := 0; i < n; i++ {
+		b.x[i], b.y[i] = r.Float64(), r.Float64()
+	}
+
+	// bucket the nodes in square cells at least as large as radius, so that only adjacent cells must be compared
+	side := int(math.Min(math.Floor(1/radius), math.Sqrt(float64(n))+1))
+	if side < 1 {
+		side = 1
+	}
+	cellOf := func(i int) (int, int) {
+		return int(math.Min(b.x[i]*float64(side), float64(side-1))), int(math.Min(b.y[i]*float64(side), float64(side-1)))
+	}
+	cellStart := make([]int32, side*side+1)
+	for i := 0; i < n; i++ {
+		cx, cy := cellOf(i)
+		cellStart[cy*side+cx+1]++
+	}
+	for c := 0; c < side*side; c++ {
+		cellStart[c+1] += cellStart[c]
+	}
+	next := make([]int32, side*side)
+	copy(next, cellStart)
+	cells := make([]int32, n)
+	for i := 0; i < n; i++ {
+		cx, cy := cellOf(i)
+		cells[next[cy*side+cx]] = int32(i)
+		next[cy*side+cx]++
+	}
+
+	for i := 0; i < n; i++ {
+		cx, cy := cellOf(i)
+		for y := cy - 1; y <= cy+1; y++ {
+			for x := cx - 1; x <= cx+1; x++ {
+				if x < 0 || y < 0 || x >= side || y >= side {
+					continue
+				}
+				c := y*side + x
+				for _, j := range cells[cellStart[c]:cellStart[c+1]] {
+					if int(j) <= i {
+						continue
+					}
+					if d := math.Hypot(b.x[i]-b.x[j], b.y[i]-b.y[j]); d < radius {
+						w := distanceWeight(d, 1)
+						b.addLink(i, int(j), w, w)
+					}
+				}
+			}
+		}
+	}
+	return b.build()
+}
+
+// BarabasiAlbert returns a scale-free graph of n nodes grown by preferential attachment: every new node is linked
+// to m distinct existing nodes chosen with probability proportional to their degree. Every link is made of two edges,
+// one per direction, of independent random weight between minWeight and maxWeight.
+func BarabasiAlbert(n, m, minWeight, maxWeight int, seed int64) *Graph {
+	r := rand.New(rand.NewSource(seed))
+	b := newBuilder(n, 2*n*m)
+	// every node appears in targets once per link it takes part in
+	targets := make([]int32, 0, 2*n*m)
+	chosen := make([]int32, 0, m)
+	for i := 0; i < n; i++ {
+		chosen = chosen[:0]
+		for len(chosen) < m && len(chosen) < i {
+			var t int32
+			if len(targets) == 0 {
+				t = int32(r.Intn(i))
+			} else {
+				t = targets[r.Intn(len(targets))]
+			}
+			if !contains(chosen, t) {
+				chosen = append(chosen, t)
+			}
+		}
+		for _, t := range chosen {
+			b.addLink(i, int(t), randomWeight(r, minWeight, maxWeight), randomWeight(r, minWeight, maxWeight))
+			targets = append(targets, int32(i), t)
+		}
+	}
+	return b.build()
+}
+
+// WattsStrogatz returns a small-world graph of n nodes: a ring where every node is linked to its k nearest neighbours
+// (k/2 on each side), each link being rewired to a random node with probability beta. Every link is made of two edges,
+// one per direction, of independent random weight between minWeight and maxWeight.
+func WattsStrogatz(n, k int, beta float64, minWeight, maxWeight int, seed int64) *Graph {
+	r := rand.New(rand.NewSource(seed))
+	b := newBuilder(n, n*k)
+	neighbours := make([][]int32, n)
+	for i := 0; i < n; i++ {
+		for d := 1; d <= k/2; d++ {
+			j := (i + d) % n
+			if r.Float64() < beta {
+				// rewire avoiding self-loops and duplicate links, giving up after a few attempts
+				for a := 0; a < 10; a++ {
+					t := r.Intn(n)
+					if t != i && !contains(neighbours[i], int32(t)) {
+						j = t
+						break
+					}
+				}
+			}
+			if j == i || contains(neighbours[i], int32(j)) {
+				continue
+			}
+			neighbours[i] = append(neighbours[i], int32(j))
+			neighbours[j] = append(neighbours[j], int32(i))
+			b.addLink(i, j, randomWeight(r, minWeight, maxWeight), randomWeight(r, minWeight, maxWeight))
+		}
+	}
+	return b.build()
+}
+
+// Complete returns the complete directed graph of n nodes, whose edges have random weight between minWeight and maxWeight.
+func Complete(n, minWeight, maxWeight int, seed int64) *Graph {
+	r := rand.New(rand.NewSource(seed))
+	b := newBuilder(n, n*(n-1))
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if i != j {
+				b.addEdge(i, j, randomWeight(r, minWeight, maxWeight))
+			}
+		}
+	}
+	return b.build()
+}
+
+// ErdosRenyi returns a random directed graph of n nodes where each of the n·(n-1) possible edges is present
+// with probability p, with a random weight between minWeight and maxWeight. It takes O(n²) time.
+func ErdosRenyi(n int, p float64, minWeight, maxWeight int, seed int64) *Graph {
+	r := rand.New(rand.NewSource(seed))
+	b := newBuilder(n, int(p*float64(n)*float64(n-1)))
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if i != j && r.Float64() < p {
+				b.addEdge(i, j, randomWeight(r, minWeight, maxWeight))
+			}
+		}
+	}
+	return b.build()
+}
+
+// DAG returns a directed acyclic graph of n nodes where every node i has degree edges (fewer near the end)
+// going to distinct random nodes j > i, of random weight between minWeight and maxWeight.
+// The node indices are therefore a topological order of the graph.
+func DAG(n, degree, minWeight, maxWeight int, seed int64) *Graph {
+	r := rand.New(rand.NewSource(seed))
+	b := newBuilder(n, n*degree)
+	chosen := make([]int32, 0, degree)
+	for i := 0; i < n-1; i++ {
+		chosen = chosen[:0]
+		for len(chosen) < degree && len(chosen) < n-1-i {
+			j := int32(i + 1 + r.Intn(n-1-i))
+			if !contains(chosen, j) {
+				chosen = append(chosen, j)
+			}
+		}
+		for _, j := range chosen {
+			b.addEdge(i, int(j), randomWeight(r, minWeight, maxWeight))
+		}
+	}
+	return b.build()
+}
+
+// RoadLike returns a planar graph resembling a road network: nodes are placed on a jittered rows x cols lattice and
+// linked in both directions to their horizontal neighbours, and to their vertical neighbours on three links out of four.
+// Every ArterialSpacing rows and columns the roads are arterial: they are never interrupted and are twice as fast.
+// Edges weigh their length, divided by their speed, times DistanceScale. Node i*cols + j lies in row i and column j.
+func RoadLike(rows, cols int, seed int64) *Graph {
+	r := rand.New(rand.NewSource(seed))
+	b := newBuilder(rows*cols, 4*rows*cols)
+	b.x, b.y = make([]float64, rows*cols), make([]float64, rows*cols)
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			u := i*cols + j
+			b.x[u], b.y[u] = float64(j)+0.6*(r.Float64()-0.5), float64(i)+0.6*(r.Float64()-0.5)
+		}
+	}
+	link := func(u, v int, arterial bool) {
+		speed := 1.0
+		if arterial {
+			speed = 2
+		}
+		w := distanceWeight(math.Hypot(b.x[u]-b.x[v], b.y[u]-b.y[v]), speed)
+		b.addLink(u, v, w, w)
+	}
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			u := i*cols + j
+			// horizontal roads are never interrupted, so that every node can reach an arterial column
+			if j+1 < cols {
+				link(u, u+1, i%ArterialSpacing == 0)
+			}
+			if i+1 < rows && (j%ArterialSpacing == 0 || r.Intn(4) != 0) {
+				link(u, u+cols, j%ArterialSpacing == 0)
+			}
+		}
+	}
+	return b.build()
+}
+
+func randomWeight(r *rand.Rand, minWeight, maxWeight int) int {
+	if maxWeight <= minWeight {
+		return minWeight
+	}
+	return minWeight + r.Intn(maxWeight-minWeight+1)
+}
+
+// distanceWeight returns the weight of an edge of length d travelled at speed.
+func distanceWeight(d, speed float64) int {
+	w := int(math.Round(d / speed * DistanceScale))
+	if w < 1 {
+		w = 1
+	}
+	return w
+}
+
+func contains(s []int32, v int32) bool {
+	for _, x := range s {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2013 Alessandro Frossi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package Generate implements seeded generators of synthetic graph objects for benchmarks and load tests.
+//
+// The generated graphs are stored in compressed adjacency arrays, so that graphs of millions of nodes fit in memory.
+// Nodes are named after their index ("0", "1", ...); geometric and road-like graphs also have coordinates.
+// Every generator is deterministic: the same parameters and seed always produce the same graph.
+package generate
+
+import (
+	"github.com/kirves/godijkstra/common/structs"
+	"strconv"
+)
+
+// Graph is a compact directed graph object built by the generators of this package.
+type Graph struct {
+	names []string
+
+	// edges leaving node i are targets[offsets[i]:offsets[i+1]], with the matching weights
+	offsets []int32
+	targets []int32
+	weights []int32
+
+	// edges reaching node i, same layout
+	rOffsets []int32
+	rTargets []int32
+	rWeights []int32
+
+	x, y []float64 // Node coordinates (nil if the graph has none)
+
+	succs, preds []dijkstrastructs.Connection // Connections laid out as targets and rTargets (nil if not cached)
+}
+
+// NodeCount returns the number of nodes of the graph.
+func (g *Graph) NodeCount() int {
+	return len(g.names)
+}
+
+// EdgeCount returns the number of edges of the graph.
+func (g *Graph) EdgeCount() int {
+	return len(g.targets)
+}
+
+// Node returns the name of the i-th node.
+func (g *Graph) Node(i int) string {
+	return g.names[i]
+}
+
+// Nodes returns the names of all the nodes, by index.
+func (g *Graph) Nodes() []string {
+	return g.names
+}
+
+// Index returns the index of node. The second return value is false if node is not in the graph.
+func (g *Graph) Index(node string) (int, bool) {
+	i, err := strconv.Atoi(node)
+	if err != nil || i < 0 || i >= len(g.names) || g.names[i] != node {
+		return 0, false
+	}
+	return i, true
+}
+
+// Coordinates returns the position of node in the plane.
+// The third return value is false if node is not in the graph or the graph has no coordinates.
+func (g *Graph) Coordinates(node string) (float64, float64, bool) {
+	i, ok := g.Index(node)
+	if !ok || g.x == nil {
+		return 0, 0, false
+	}
+	return g.x[i], g.y[i], true
+}
+
+func (g *Graph) SuccessorsForNode(node string) []dijkstrastructs.Connection {
+	i, ok := g.Index(node)
+	if !ok {
+		return nil
+	}
+	return g.connections(g.offsets, g.targets, g.weights, g.succs, i)
+}
+
+func (g *Graph) PredecessorsFromNode(node string) []dijkstrastructs.Connection {
+	i, ok := g.Index(node)
+	if !ok {
+		return nil
+	}
+	return g.connections(g.rOffsets, g.rTargets, g.rWeights, g.preds, i)
+}
+
+// EdgeWeight returns the weight of the lightest edge going from n1 to n2, or -1 if there is none.
+func (g *Graph) EdgeWeight(n1, n2 string) int {
+	i, ok := g.Index(n1)
+	if !ok {
+		return -1
+	}
+	j, ok := g.Index(n2)
+	if !ok {
+		return -1
+	}
+	w := -1
+	for e := g.offsets[i]; e < g.offsets[i+1]; e++ {
+		if int(g.targets[e]) == j && (w < 0 || int(g.weights[e]) < w) {
+			w = int(g.weights[e])
+		}
+	}
+	return w
+}
+
+// CacheConnections builds the connections of every node once, so that SuccessorsForNode and PredecessorsFromNode
+// return them without allocating memory. The cache takes about 100 bytes per edge; the returned connections
+// are then shared and must not be modified.
+func (g *Graph) CacheConnections() {
+	if g.succs != nil {
+		return
+	}
+	g.succs = g.allConnections(g.targets, g.weights)
+	g.preds = g.allConnections(g.rTargets, g.rWeights)
+}
+
+func (g *Graph) allConnections(targets, weights []int32) []dijkstrastructs.Connection {
+	ret := make([]dijkstrastructs.Connection, len(targets))
+	for e := range targets {
+		ret[e] = dijkstrastructs.Connection{Destination: g.names[targets[e]], Weight: int(weights[e])}
+	}
+	return ret
+}
+
+func (g *Graph) connections(offsets, targets, weights []int32, cache []dijkstrastructs.Connection, i int) []dijkstrastructs.Connection {
+	if cache != nil {
+		return cache[offsets[i]:offsets[i+1]:offsets[i+1]]
+	}
+	ret := make([]dijkstrastructs.Connection, 0, offsets[i+1]-offsets[i])
+	for e := offsets[i]; e < offsets[i+1]; e++ {
+		ret = append(ret, dijkstrastructs.Connection{Destination: g.names[targets[e]], Weight: int(weights[e])})
+	}
+	return ret
+}
+
+// builder collects the edges of a graph before compressing them.
+type builder struct {
+	n        int
+	from, to []int32
+	weights  []int32
+	x, y     []float64
+}
+
+func newBuilder(n, edges int) *builder {
+	return &builder{
+		n:       n,
+		from:    make([]int32, 0, edges),
+		to:      make([]int32, 0, edges),
+		weights: make([]int32, 0, edges),
+	}
+}
+
+func (b *builder) addEdge(from, to, w int) {
+	b.from = append(b.from, int32(from))
+	b.to = append(b.to, int32(to))
+	b.weights = append(b.weights, int32(w))
+}
+
+// addLink adds an edge in both directions, with weights w1 and w2.
+func (b *builder) addLink(u, v, w1, w2 int) {
+	b.addEdge(u, v, w1)
+	b.addEdge(v, u, w2)
+}
+
+// build compresses the collected edges, keeping their insertion order within each node.
+func (b *builder) build() *Graph {
+	g := &Graph{names: make([]string, b.n), x: b.x, y: b.y}
+	for i := range g.names {
+		g.names[i] = strconv.Itoa(i)
+	}
+	g.offsets, g.targets, g.weights = compress(b.n, b.from, b.to, b.weights)
+	g.rOffsets, g.rTargets, g.rWeights = compress(b.n, b.to, b.from, b.weights)
+	return g
+}
+
+// compress groups the edges by source node with a counting sort.
+func compress(n int, from, to, weights []int32) ([]int32, []int32, []int32) {
+	offsets := make([]int32, n+1)
+	for _, f := range from {
+		offsets[f+1]++
+	}
+	for i := 0; i < n; i++ {
+		offsets[i+1] += offsets[i]
+	}
+	next := make([]int32, n)
+	copy(next, offsets[0:n])
+	targets := make([]int32, len(to))
+	ws := make([]int32, len(weights))
+	for e, f := range from {
+		targets[next[f]] = to[e]
+		ws[next[f]] = weights[e]
+		next[f]++
+	}
+	return offsets, targets, ws
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2013 Alessandro Frossi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graphtest
+
+import (
+	"fmt"
+	"github.com/kirves/godijkstra/generate"
+)
+
+// NodeName returns the name of the i-th node of the generated graphs.
+func NodeName(i int) string {
+	return fmt.Sprint("N", i)
+}
+
+// ErdosRenyi returns a random graph of n nodes where each of the n·(n-1) possible edges is present with probability p,
+// as generate.ErdosRenyi does, with edge weights between 1 and maxWeight.
+func ErdosRenyi(n int, p float64, maxWeight int, seed int64) *Graph {
+	return fromGenerated(generate.ErdosRenyi(n, p, 1, maxWeight, seed))
+}
+
+// Grid returns a rows x cols grid whose adjacent nodes are linked in both directions, as generate.Grid does,
+// with edge weights between 1 and maxWeight. The node in row i and column j is NodeName(i*cols + j).
+func Grid(rows, cols, maxWeight int, seed int64) *Graph {
+	return fromGenerated(generate.Grid(rows, cols, 1, maxWeight, seed))
+}
+
+// ScaleFree returns a random graph of n nodes grown by preferential attachment, as generate.BarabasiAlbert does,
+// with edge weights between 1 and maxWeight.
+func ScaleFree(n, m, maxWeight int, seed int64) *Graph {
+	return fromGenerated(generate.BarabasiAlbert(n, m, 1, maxWeight, seed))
+}
+
+// fromGenerated copies a generated graph into a Graph, naming its i-th node NodeName(i).
+func fromGenerated(gen *generate.Graph) *Graph {
+	g := NewGraph()
+	for i := 0; i < gen.NodeCount(); i++ {
+		g.AddNode(NodeName(i))
+	}
+	for i, n := range gen.Nodes() {
+		for _, c := range gen.SuccessorsForNode(n) {
+			j, _ := gen.Index(c.Destination)
+			g.AddEdge(NodeName(i), NodeName(j), "", c.Weight)
+		}
+	}
+	return g
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2013 Alessandro Frossi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package Graphtest provides random graph generators and a test harness to check path search algorithms.
+//
+// The generators build small seeded graphs (Erdős–Rényi, grid and scale-free) with the generate package and copy them
+// into a mutable Graph, on which the result of any search function can be compared with a brute-force reference that
+// enumerates every loopless path, checking the weight of the returned paths, their validity and, for k-shortest paths
+// algorithms such as Yen's, the order of the solutions.
+package graphtest
+
+import (
+	"github.com/kirves/godijkstra/common/structs"
+)
+
+// Graph is a mutable directed graph object supporting parallel edges, used as a test fixture.
+type Graph struct {
+	nodes        []string
+	index        map[string]int
+	edges        map[string][]dijkstrastructs.Connection
+	reverseEdges map[string][]dijkstrastructs.Connection
+}
+
+// NewGraph returns an empty graph.
+func NewGraph() *Graph {
+	return &Graph{
+		index:        make(map[string]int),
+		edges:        make(map[string][]dijkstrastructs.Connection),
+		reverseEdges: make(map[string][]dijkstrastructs.Connection),
+	}
+}
+
+// AddNode adds node to the graph, if not already present.
+func (g *Graph) AddNode(node string) {
+	if _, ok := g.index[node]; !ok {
+		g.index[node] = len(g.nodes)
+		g.nodes = append(g.nodes, node)
+	}
+}
+
+// AddEdge adds an edge going from node from to node to, adding the nodes as well.
+func (g *Graph) AddEdge(from, to, id string, w int) {
+	g.AddEdgeWithAttributes(from, to, id, w, nil)
+}
+
+// AddEdgeWithAttributes adds an edge going from node from to node to carrying the given attributes.
+func (g *Graph) AddEdgeWithAttributes(from, to, id string, w int, attrs map[string]interface{}) {
+	g.AddNode(from)
+	g.AddNode(to)
+	g.edges[from] = append(g.edges[from], dijkstrastructs.Connection{Destination: to, Weight: w, ID: id, Attributes: attrs})
+	g.reverseEdges[to] = append(g.reverseEdges[to], dijkstrastructs.Connection{Destination: from, Weight: w, ID: id, Attributes: attrs})
+}
+
+// AddSuccessor lists an edge going from node from to node to among the successors of from only,
+// making the graph inconsistent on purpose.
+func (g *Graph) AddSuccessor(from, to, id string, w int) {
+	g.AddNode(from)
+	g.AddNode(to)
+	g.edges[from] = append(g.edges[from], dijkstrastructs.Connection{Destination: to, Weight: w, ID: id})
+}
+
+// AddPredecessor lists an edge going from node from to node to among the predecessors of to only,
+// making the graph inconsistent on purpose.
+func (g *Graph) AddPredecessor(from, to, id string, w int) {
+	g.AddNode(from)
+	g.AddNode(to)
+	g.reverseEdges[to] = append(g.reverseEdges[to], dijkstrastructs.Connection{Destination: from, Weight: w, ID: id})
+}
+
+// RemoveEdges removes every edge going from node from to node to.
+func (g *Graph) RemoveEdges(from, to string) {
+	g.edges[from] = removeConnections(g.edges[from], to)
+	g.reverseEdges[to] = removeConnections(g.reverseEdges[to], from)
+}
+
+// Nodes returns the nodes of the graph, in insertion order.
+func (g *Graph) Nodes() []string {
+	return g.nodes
+}
+
+// EdgeCount returns the number of edges of the graph.
+func (g *Graph) EdgeCount() int {
+	n := 0
+	for _, conns := range g.edges {
+		n += len(conns)
+	}
+	return n
+}
+
+func (g *Graph) SuccessorsForNode(node string) []dijkstrastructs.Connection {
+	return g.edges[node]
+}
+
+func (g *Graph) PredecessorsFromNode(node string) []dijkstrastructs.Connection {
+	return g.reverseEdges[node]
+}
+
+// EdgeWeight returns the weight of the lightest edge going from n1 to n2, or -1 if there is none.
+func (g *Graph) EdgeWeight(n1, n2 string) int {
+	w := -1
+	for _, c := range g.edges[n1] {
+		if c.Destination == n2 && (w < 0 || c.Weight < w) {
+			w = c.Weight
+		}
+	}
+	return w
+}
+
+// removeConnections filters out of conns, in place, the connections to node.
+func removeConnections(conns []dijkstrastructs.Connection, node string) []dijkstrastructs.Connection {
+	ret := conns[:0]
+	for _, c := range conns {
+		if c.Destination != node {
+			ret = append(ret, c)
+		}
+	}
+	return ret
+}
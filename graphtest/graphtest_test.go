@@ -0,0 +1,123 @@
+/*
+Copyright 2013 Alessandro Frossi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graphtest
+
+import (
+	"github.com/kirves/godijkstra/common/path"
+	"github.com/kirves/godijkstra/common/structs"
+	"github.com/kirves/godijkstra/dijkstra"
+	"github.com/kirves/godijkstra/yen"
+	"testing"
+)
+
+// smallGraphs returns graphs small enough to enumerate all their paths.
+func smallGraphs(seed int64) []*Graph {
+	return []*Graph{
+		ErdosRenyi(8, 0.3, 5, seed),
+		Grid(3, 3, 5, seed),
+		ScaleFree(9, 2, 5, seed),
+	}
+}
+
+func TestGenerators(t *testing.T) {
+	for seed := int64(0); seed < 3; seed++ {
+		gs, hs := smallGraphs(seed), smallGraphs(seed)
+		for i := range gs {
+			for _, n := range gs[i].Nodes() {
+				c1, c2 := gs[i].SuccessorsForNode(n), hs[i].SuccessorsForNode(n)
+				if len(c1) != len(c2) {
+					t.Fatalf("Non reproducible graph %d (seed %d)", i, seed)
+				}
+				for j := range c1 {
+					if c1[j].Destination != c2[j].Destination || c1[j].Weight != c2[j].Weight {
+						t.Fatalf("Non reproducible graph %d (seed %d)", i, seed)
+					}
+				}
+			}
+		}
+	}
+
+	g := Grid(3, 4, 5, 0)
+	if len(g.Nodes()) != 12 || g.EdgeCount() != 2*(2*3*4-3-4) {
+		t.Fatalf("Wrong grid size: %d nodes, %d edges", len(g.Nodes()), g.EdgeCount())
+	}
+	g = ScaleFree(20, 3, 5, 0)
+	if len(g.Nodes()) != 20 || g.EdgeCount() != 2*(3+3*(20-3)) {
+		t.Fatalf("Wrong scale-free graph size: %d nodes, %d edges", len(g.Nodes()), g.EdgeCount())
+	}
+}
+
+func TestAllPaths(t *testing.T) {
+	g := NewGraph()
+	g.AddEdge("S", "A", "", 1)
+	g.AddEdge("S", "B", "", 1)
+	g.AddEdge("A", "B", "", 1)
+	g.AddEdge("B", "A", "", 3)
+	g.AddEdge("A", "T", "", 5)
+	g.AddEdge("B", "T", "", 1)
+	paths := AllPaths(g, "S", "T", dijkstrastructs.EmptyUnusableEdgeMap())
+	expWeights := []int{2, 3, 6, 9}
+	if len(paths) != len(expWeights) {
+		t.Fatalf("Wrong number of paths:\nExpected: %d\nGot: %d\n", len(expWeights), len(paths))
+	}
+	for i, p := range paths {
+		if p.Weight != expWeights[i] {
+			t.Fatalf("Wrong path weight (%d):\nExpected: %d\nGot: %d\n", i, expWeights[i], p.Weight)
+		}
+		if err := CheckPath(g, p, "S", "T", dijkstrastructs.EmptyUnusableEdgeMap()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	banned := dijkstrastructs.EmptyUnusableEdgeMap()
+	banned.Ban("B", "T")
+	if err := CheckPath(g, paths[0], "S", "T", banned); err == nil {
+		t.Fatal("Path using a banned edge accepted.")
+	}
+	if len(AllPaths(g, "S", "T", banned)) != 2 {
+		t.Fatal("Wrong number of paths avoiding a banned edge.")
+	}
+	broken := dijkstrapath.DijkstraPath{Path: append([]dijkstrapath.DijkstraPathElement(nil), paths[0].Path...), Weight: paths[0].Weight}
+	broken.Path[1].Weight = 2
+	if err := CheckPath(g, broken, "S", "T", dijkstrastructs.EmptyUnusableEdgeMap()); err == nil {
+		t.Fatal("Path with inconsistent weights accepted.")
+	}
+}
+
+func TestSearch(t *testing.T) {
+	for seed := int64(0); seed < 5; seed++ {
+		for _, g := range smallGraphs(seed) {
+			for _, searchType := range []int{dijkstra.VANILLA, dijkstra.BIDIR, dijkstra.DIAL} {
+				CheckSearch(t, g, SearchFunc(dijkstra.SearchFunc(searchType, dijkstra.SearchOptions{})), 30, seed)
+				CheckSearch(t, g, SearchFunc(dijkstra.NewSearcher(dijkstra.SearchOptions{}).SearchFunc(searchType)), 30, seed)
+			}
+		}
+	}
+}
+
+func TestYen(t *testing.T) {
+	for seed := int64(0); seed < 5; seed++ {
+		for _, g := range smallGraphs(seed) {
+			for _, searchType := range []int{dijkstra.VANILLA, dijkstra.BIDIR} {
+				search := dijkstra.SearchFunc(searchType, dijkstra.SearchOptions{})
+				CheckKShortest(t, g, func(graph dijkstrastructs.GraphObject, startNode, endNode string, k int) []dijkstrapath.DijkstraPath {
+					return yen.Yen(graph, startNode, endNode, k, search)
+				}, 5, 10, seed)
+			}
+		}
+	}
+}
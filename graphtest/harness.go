@@ -0,0 +1,217 @@
+/*
+Copyright 2013 Alessandro Frossi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graphtest
+
+import (
+	"fmt"
+	"github.com/kirves/godijkstra/common/path"
+	"github.com/kirves/godijkstra/common/structs"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// SearchFunc is a shortest path search function, such as dijkstra.Dijkstra.
+type SearchFunc func(dijkstrastructs.GraphObject, string, string, dijkstrastructs.UnusableEdgeMap) (dijkstrapath.DijkstraPath, bool)
+
+// KShortestFunc returns the k shortest paths going from startNode to endNode, such as yen.Yen bound to a search function.
+type KShortestFunc func(graph dijkstrastructs.GraphObject, startNode, endNode string, k int) []dijkstrapath.DijkstraPath
+
+// AllPaths returns every loopless path going from startNode to endNode within graph without using bannedEdges,
+// sorted by increasing weight. Since the number of such paths grows exponentially, it is meant for small graphs only.
+func AllPaths(graph dijkstrastructs.GraphObject, startNode, endNode string, bannedEdges dijkstrastructs.UnusableEdgeMap) []dijkstrapath.DijkstraPath {
+	ret := make([]dijkstrapath.DijkstraPath, 0)
+	onPath := map[string]bool{startNode: true}
+	elements := []dijkstrapath.DijkstraPathElement{dijkstrapath.DijkstraPathElement{Node: startNode}}
+
+	var visit func()
+	visit = func() {
+		last := elements[len(elements)-1]
+		if last.Node == endNode {
+			p := dijkstrapath.DijkstraPath{
+				Path:      append([]dijkstrapath.DijkstraPathElement(nil), elements...),
+				Weight:    last.Weight,
+				StartNode: startNode,
+				EndNode:   endNode,
+			}
+			ret = append(ret, p)
+			return
+		}
+		for _, c := range graph.SuccessorsForNode(last.Node) {
+			if onPath[c.Destination] || bannedEdges.IsBanned(last.Node, c.Destination, c.ID) {
+				continue
+			}
+			onPath[c.Destination] = true
+			elements = append(elements, dijkstrapath.DijkstraPathElement{
+				Node:           c.Destination,
+				Weight:         last.Weight + c.Weight,
+				EdgeID:         c.ID,
+				EdgeAttributes: c.Attributes,
+			})
+			visit()
+			elements = elements[0 : len(elements)-1]
+			onPath[c.Destination] = false
+		}
+	}
+	visit()
+
+	sort.Stable(byWeight(ret))
+	return ret
+}
+
+// CheckPath returns an error describing why p is not a valid loopless path going from startNode to endNode within graph
+// without using bannedEdges, or nil if it is. The weight of every element of p must be the weight of the path up to its node.
+func CheckPath(graph dijkstrastructs.GraphObject, p dijkstrapath.DijkstraPath, startNode, endNode string, bannedEdges dijkstrastructs.UnusableEdgeMap) error {
+	if len(p.Path) == 0 {
+		return fmt.Errorf("empty path")
+	}
+	if p.Path[0].Node != startNode || p.Path[len(p.Path)-1].Node != endNode {
+		return fmt.Errorf("path %v does not go from %s to %s", p.Path, startNode, endNode)
+	}
+	if p.Path[0].Weight != 0 {
+		return fmt.Errorf("path %v does not start with weight 0", p.Path)
+	}
+	seen := make(map[string]bool)
+	for i, e := range p.Path {
+		if seen[e.Node] {
+			return fmt.Errorf("path %v visits %s twice", p.Path, e.Node)
+		}
+		seen[e.Node] = true
+		if i == 0 {
+			continue
+		}
+		from := p.Path[i-1]
+		found := false
+		for _, c := range graph.SuccessorsForNode(from.Node) {
+			if c.Destination == e.Node && c.ID == e.EdgeID && c.Weight == e.Weight-from.Weight {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("path %v uses a missing edge %s -> %s (id %q, weight %d)", p.Path, from.Node, e.Node, e.EdgeID, e.Weight-from.Weight)
+		}
+		if bannedEdges.IsBanned(from.Node, e.Node, e.EdgeID) {
+			return fmt.Errorf("path %v uses the banned edge %s -> %s (id %q)", p.Path, from.Node, e.Node, e.EdgeID)
+		}
+	}
+	if w := p.Path[len(p.Path)-1].Weight; p.Weight != w {
+		return fmt.Errorf("path %v has weight %d instead of %d", p.Path, p.Weight, w)
+	}
+	return nil
+}
+
+// CheckSearch runs search on queries random pairs of nodes of g and compares the results with AllPaths:
+// the returned path must be valid and as light as the lightest reference path, and no path must be returned
+// if there is none. One query out of three bans some random edges.
+func CheckSearch(t testing.TB, g *Graph, search SearchFunc, queries int, seed int64) {
+	t.Helper()
+	r := rand.New(rand.NewSource(seed))
+	nodes := g.Nodes()
+	if len(nodes) == 0 {
+		return
+	}
+	for q := 0; q < queries; q++ {
+		s, e := nodes[r.Intn(len(nodes))], nodes[r.Intn(len(nodes))]
+		bannedEdges := dijkstrastructs.EmptyUnusableEdgeMap()
+		if q%3 == 2 {
+			bannedEdges = randomBannedEdges(g, r)
+		}
+		ref := AllPaths(g, s, e, bannedEdges)
+		p, valid := search(g, s, e, bannedEdges)
+		switch {
+		case len(ref) == 0 && valid:
+			t.Errorf("query %d (seed %d): found path %v from %s to %s, none expected", q, seed, p.Path, s, e)
+		case len(ref) > 0 && !valid:
+			t.Errorf("query %d (seed %d): no path from %s to %s, expected %v", q, seed, s, e, ref[0].Path)
+		case valid && p.Weight != ref[0].Weight:
+			t.Errorf("query %d (seed %d): path %v from %s to %s has weight %d, expected %d", q, seed, p.Path, s, e, p.Weight, ref[0].Weight)
+		case valid:
+			if err := CheckPath(g, p, s, e, bannedEdges); err != nil {
+				t.Errorf("query %d (seed %d): %v", q, seed, err)
+			}
+		}
+	}
+}
+
+// CheckKShortest runs ksp on queries random pairs of distinct nodes of g and compares the results with AllPaths:
+// the returned paths must be valid and distinct, as many as the reference paths up to k, and their weights must match
+// the weights of the k lightest reference paths, in the same order.
+func CheckKShortest(t testing.TB, g *Graph, ksp KShortestFunc, k, queries int, seed int64) {
+	t.Helper()
+	r := rand.New(rand.NewSource(seed))
+	nodes := g.Nodes()
+	if len(nodes) < 2 {
+		return
+	}
+	for q := 0; q < queries; q++ {
+		s, e := nodes[r.Intn(len(nodes))], nodes[r.Intn(len(nodes))]
+		for s == e {
+			e = nodes[r.Intn(len(nodes))]
+		}
+		ref := AllPaths(g, s, e, dijkstrastructs.EmptyUnusableEdgeMap())
+		if len(ref) > k {
+			ref = ref[0:k]
+		}
+		paths := ksp(g, s, e, k)
+		if len(paths) != len(ref) {
+			t.Errorf("query %d (seed %d): found %d paths from %s to %s, expected %d", q, seed, len(paths), s, e, len(ref))
+			continue
+		}
+		for i, p := range paths {
+			if err := CheckPath(g, p, s, e, dijkstrastructs.EmptyUnusableEdgeMap()); err != nil {
+				t.Errorf("query %d (seed %d), path %d: %v", q, seed, i, err)
+			}
+			if p.Weight != ref[i].Weight {
+				t.Errorf("query %d (seed %d): path %d from %s to %s has weight %d, expected %d", q, seed, i, s, e, p.Weight, ref[i].Weight)
+			}
+			for j := 0; j < i; j++ {
+				if p.IsEqual(paths[j]) {
+					t.Errorf("query %d (seed %d): paths %d and %d from %s to %s are equal: %v", q, seed, j, i, s, e, p.Path)
+				}
+			}
+		}
+	}
+}
+
+// randomBannedEdges bans about one edge of g out of ten.
+func randomBannedEdges(g *Graph, r *rand.Rand) dijkstrastructs.UnusableEdgeMap {
+	ret := dijkstrastructs.EmptyUnusableEdgeMap()
+	for _, n := range g.Nodes() {
+		for _, c := range g.SuccessorsForNode(n) {
+			if r.Intn(10) == 0 {
+				ret.BanEdge(n, c.Destination, c.ID)
+			}
+		}
+	}
+	return ret
+}
+
+// byWeight sorts paths by increasing weight.
+type byWeight []dijkstrapath.DijkstraPath
+
+func (s byWeight) Len() int {
+	return len(s)
+}
+
+func (s byWeight) Less(i, j int) bool {
+	return s[i].Weight < s[j].Weight
+}
+
+func (s byWeight) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
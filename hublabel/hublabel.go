@@ -0,0 +1,291 @@
+/*
+Copyright 2013 Alessandro Frossi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package Hublabel implements a hub labeling index to answer distance queries by label intersection.
+//
+// The index is built once from a graph object using pruned landmark labeling: nodes are ranked by degree and,
+// for each node in rank order, a forward and a backward Dijkstra search are run and pruned as soon as the
+// labels computed so far already cover the visited pair. Every node then stores the hubs it can reach (forward label)
+// and the hubs that can reach it (backward label), so that the distance between two nodes is the minimum
+// over the hubs the two labels have in common.
+//
+// The index only stores distances; the actual path can be rebuilt with a Dijkstra search restricted to the edges
+// lying on a shortest path, which the index is able to recognize.
+package hublabel
+
+import (
+	"container/heap"
+	"github.com/kirves/godijkstra/common/path"
+	"github.com/kirves/godijkstra/common/structs"
+	"github.com/kirves/godijkstra/dijkstra"
+	"sort"
+)
+
+// labelEntry is a single hub of a node label.
+type labelEntry struct {
+	hub    int // Rank of the hub node
+	weight int // Distance between the labeled node and the hub
+}
+
+// HubLabeling is a distance index over a graph object.
+// It is read-only once built and can be queried concurrently.
+type HubLabeling struct {
+	graph dijkstrastructs.GraphObject
+	rank  map[string]int // Rank of every indexed node (lower is more important)
+	nodes []string       // Indexed nodes sorted by rank
+	forw  [][]labelEntry // Hubs reachable from each node, sorted by rank
+	back  [][]labelEntry // Hubs reaching each node, sorted by rank
+}
+
+// NewHubLabeling builds the hub labeling index of graph.
+// nodes should list every node of the graph: nodes that are not listed but are reached while building the index
+// are appended with the lowest ranks, nodes that are unreachable from every listed node are not indexed.
+func NewHubLabeling(graph dijkstrastructs.GraphObject, nodes []string) *HubLabeling {
+	hl := &HubLabeling{graph: graph, rank: make(map[string]int)}
+
+	ordered := make([]string, len(nodes))
+	copy(ordered, nodes)
+	sort.Sort(byDegree{ordered, degrees(graph, ordered)})
+	for _, n := range ordered {
+		hl.indexOf(n)
+	}
+
+	// nodes reached during the searches are appended to hl.nodes and processed as well
+	for r := 0; r < len(hl.nodes); r++ {
+		hl.prunedSearch(r, true)
+		hl.prunedSearch(r, false)
+	}
+	return hl
+}
+
+// Distance returns the weight of the shortest path going from node u to node v.
+// The second return value is false if v cannot be reached from u or either node is not indexed.
+func (hl *HubLabeling) Distance(u, v string) (int, bool) {
+	ui, ok := hl.rank[u]
+	if !ok {
+		return 0, false
+	}
+	vi, ok := hl.rank[v]
+	if !ok {
+		return 0, false
+	}
+	return hl.distance(ui, vi)
+}
+
+// Path returns the shortest path going from node u to node v.
+// The path is computed by a Dijkstra search restricted to the edges lying on a shortest path between u and v,
+// as stated by the index.
+func (hl *HubLabeling) Path(u, v string) (dijkstrapath.DijkstraPath, bool) {
+	w, ok := hl.Distance(u, v)
+	if !ok {
+		return dijkstrapath.DijkstraPath{}, false
+	}
+	rg := &restrictedGraph{hl, u, v, w}
+	dp, valid := dijkstra.Dijkstra(rg, u, v, dijkstrastructs.EmptyUnusableEdgeMap())
+	if !valid || dp.Weight != w {
+		return dijkstrapath.DijkstraPath{}, false
+	}
+	return dp, true
+}
+
+// LabelSize returns the average number of hubs stored for each indexed node.
+func (hl *HubLabeling) LabelSize() float64 {
+	if len(hl.nodes) == 0 {
+		return 0
+	}
+	total := 0
+	for i := range hl.nodes {
+		total += len(hl.forw[i]) + len(hl.back[i])
+	}
+	return float64(total) / float64(len(hl.nodes))
+}
+
+func (hl *HubLabeling) indexOf(node string) int {
+	if i, ok := hl.rank[node]; ok {
+		return i
+	}
+	i := len(hl.nodes)
+	hl.rank[node] = i
+	hl.nodes = append(hl.nodes, node)
+	hl.forw = append(hl.forw, nil)
+	hl.back = append(hl.back, nil)
+	return i
+}
+
+// distance intersects the forward label of u with the backward label of v.
+func (hl *HubLabeling) distance(u, v int) (int, bool) {
+	lf, lb := hl.forw[u], hl.back[v]
+	best, found := 0, false
+	for i, j := 0, 0; i < len(lf) && j < len(lb); {
+		switch {
+		case lf[i].hub < lb[j].hub:
+			i++
+		case lf[i].hub > lb[j].hub:
+			j++
+		default:
+			if w := lf[i].weight + lb[j].weight; !found || w < best {
+				best, found = w, true
+			}
+			i++
+			j++
+		}
+	}
+	return best, found
+}
+
+// prunedSearch runs the pruned Dijkstra search rooted at the node of rank r.
+// The forward search fills the backward labels of the visited nodes, the backward search their forward labels.
+func (hl *HubLabeling) prunedSearch(r int, forward bool) {
+	settled := make(map[int]bool)
+	openList := &entryQueue{}
+	heap.Init(openList)
+	heap.Push(openList, labelEntry{r, 0})
+
+	for openList.Len() > 0 {
+		e := heap.Pop(openList).(labelEntry)
+		if settled[e.hub] {
+			continue
+		}
+		settled[e.hub] = true
+
+		var known int
+		var covered bool
+		if forward {
+			known, covered = hl.distance(r, e.hub)
+		} else {
+			known, covered = hl.distance(e.hub, r)
+		}
+		if covered && known <= e.weight {
+			continue
+		}
+
+		var conns []dijkstrastructs.Connection
+		if forward {
+			hl.back[e.hub] = append(hl.back[e.hub], labelEntry{r, e.weight})
+			conns = hl.graph.SuccessorsForNode(hl.nodes[e.hub])
+		} else {
+			hl.forw[e.hub] = append(hl.forw[e.hub], labelEntry{r, e.weight})
+			conns = hl.graph.PredecessorsFromNode(hl.nodes[e.hub])
+		}
+		for _, c := range conns {
+			n := hl.indexOf(c.Destination)
+			if settled[n] {
+				continue
+			}
+			heap.Push(openList, labelEntry{n, e.weight + c.Weight})
+		}
+	}
+}
+
+// restrictedGraph exposes only the edges of the underlying graph that lie on a shortest path between start and end.
+type restrictedGraph struct {
+	hl         *HubLabeling
+	start, end string
+	weight     int
+}
+
+func (rg *restrictedGraph) onShortestPath(from, to string, w int) bool {
+	dFrom, ok := rg.hl.Distance(rg.start, from)
+	if !ok {
+		return false
+	}
+	dTo, ok := rg.hl.Distance(to, rg.end)
+	if !ok {
+		return false
+	}
+	return dFrom+w+dTo == rg.weight
+}
+
+func (rg *restrictedGraph) SuccessorsForNode(node string) []dijkstrastructs.Connection {
+	ret := make([]dijkstrastructs.Connection, 0)
+	for _, c := range rg.hl.graph.SuccessorsForNode(node) {
+		if rg.onShortestPath(node, c.Destination, c.Weight) {
+			ret = append(ret, c)
+		}
+	}
+	return ret
+}
+
+func (rg *restrictedGraph) PredecessorsFromNode(node string) []dijkstrastructs.Connection {
+	ret := make([]dijkstrastructs.Connection, 0)
+	for _, c := range rg.hl.graph.PredecessorsFromNode(node) {
+		if rg.onShortestPath(c.Destination, node, c.Weight) {
+			ret = append(ret, c)
+		}
+	}
+	return ret
+}
+
+func (rg *restrictedGraph) EdgeWeight(n1, n2 string) int {
+	return rg.hl.graph.EdgeWeight(n1, n2)
+}
+
+func degrees(graph dijkstrastructs.GraphObject, nodes []string) map[string]int {
+	ret := make(map[string]int)
+	for _, n := range nodes {
+		ret[n] = len(graph.SuccessorsForNode(n)) + len(graph.PredecessorsFromNode(n))
+	}
+	return ret
+}
+
+// byDegree sorts nodes by decreasing degree, breaking ties by name.
+type byDegree struct {
+	nodes  []string
+	degree map[string]int
+}
+
+func (s byDegree) Len() int {
+	return len(s.nodes)
+}
+
+func (s byDegree) Less(i, j int) bool {
+	di, dj := s.degree[s.nodes[i]], s.degree[s.nodes[j]]
+	if di != dj {
+		return di > dj
+	}
+	return s.nodes[i] < s.nodes[j]
+}
+
+func (s byDegree) Swap(i, j int) {
+	s.nodes[i], s.nodes[j] = s.nodes[j], s.nodes[i]
+}
+
+// entryQueue is a heap of label entries used by the pruned searches, where hub is the visited node.
+type entryQueue []labelEntry
+
+func (q entryQueue) Len() int {
+	return len(q)
+}
+
+func (q entryQueue) Less(i, j int) bool {
+	return q[i].weight < q[j].weight
+}
+
+func (q entryQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *entryQueue) Push(x interface{}) {
+	*q = append(*q, x.(labelEntry))
+}
+
+func (q *entryQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	x := old[n-1]
+	*q = old[0 : n-1]
+	return x
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2013 Alessandro Frossi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hublabel
+
+import (
+	"github.com/kirves/godijkstra/common/structs"
+	"github.com/kirves/godijkstra/dijkstra"
+	"github.com/kirves/godijkstra/graphtest"
+	"testing"
+)
+
+var (
+	graph *graphtest.Graph
+	index *HubLabeling
+)
+
+func init() {
+	graph = graphtest.NewGraph()
+	graph.AddEdge("S", "A", "", 2)
+	graph.AddEdge("S", "B", "", 5)
+	graph.AddEdge("A", "B", "", 1)
+	graph.AddEdge("A", "C", "", 6)
+	graph.AddEdge("B", "C", "", 2)
+	graph.AddEdge("B", "D", "", 7)
+	graph.AddEdge("C", "D", "", 1)
+	graph.AddEdge("C", "A", "", 3)
+	graph.AddEdge("D", "T", "", 2)
+	graph.AddEdge("C", "T", "", 8)
+	graph.AddEdge("T", "S", "", 4)
+	graph.AddNode("U")
+	index = NewHubLabeling(graph, graph.Nodes())
+}
+
+func TestDistance(t *testing.T) {
+	for _, u := range graph.Nodes() {
+		for _, v := range graph.Nodes() {
+			exp, valid := dijkstra.Dijkstra(graph, u, v, dijkstrastructs.EmptyUnusableEdgeMap())
+			w, ok := index.Distance(u, v)
+			if ok != valid {
+				t.Fatalf("Reachability mismatch for %s -> %s: expected %t, got %t.", u, v, valid, ok)
+			}
+			if ok && w != exp.Weight {
+				t.Fatalf("Wrong distance for %s -> %s:\nExpected: %d\nGot: %d\n", u, v, exp.Weight, w)
+			}
+		}
+	}
+}
+
+func TestPath(t *testing.T) {
+	path, valid := index.Path("S", "T")
+	if !valid {
+		t.Fatal("Validity error.")
+	}
+	expPath := []string{"S", "A", "B", "C", "D", "T"}
+	if len(path.Path) != len(expPath) {
+		t.Fatalf("Wrong path: %v", path.Path)
+	}
+	for i, v := range path.Path {
+		if v.Node != expPath[i] {
+			t.Fatalf("Wrong path: %v", path.Path)
+		}
+	}
+	if path.Weight != 8 {
+		t.Fatalf("Wrong path weight:\nExpected: %d\nGot: %d\n", 8, path.Weight)
+	}
+}
+
+func TestUnknownNode(t *testing.T) {
+	if _, ok := index.Distance("S", "Z"); ok {
+		t.Fatal("A distance was found for a node outside the graph.")
+	}
+	if _, ok := index.Path("U", "T"); ok {
+		t.Fatal("A path was found from an isolated node.")
+	}
+}
@@ -0,0 +1,270 @@
+/*
+Copyright 2013 Alessandro Frossi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package Lpastar implements Lifelong Planning A* to keep a shortest path up to date while edge weights change.
+//
+// A Planner holds the state of a search between two fixed nodes: for every visited node it stores g, the weight
+// of the best path found so far, and rhs, the one-step lookahead computed from the predecessors' g values.
+// Nodes where the two values disagree are inconsistent and are kept in a priority queue; after an edge update only
+// the nodes made inconsistent by the change are expanded again, instead of re-running the whole search.
+// Since graph objects carry no heuristic, the planner behaves as an incremental Dijkstra search.
+package lpastar
+
+import (
+	"container/heap"
+	"github.com/kirves/godijkstra/common/path"
+	"github.com/kirves/godijkstra/common/structs"
+)
+
+const infinity = int(^uint(0) >> 1)
+
+// unreached is the cost of the nodes not reached yet.
+var unreached = cost{infinity, infinity}
+
+// Planner is a stateful shortest path search between a start and a goal node, which can be repaired after
+// edge weight changes.
+// The planner reads the topology from the graph object and is not safe for concurrent use.
+type Planner struct {
+	graph     dijkstrastructs.GraphObject
+	startNode string
+	endNode   string
+	g         map[string]cost           // Cost of the best path found so far for each node
+	rhs       map[string]cost           // Lookahead cost for each node
+	weights   map[string]map[string]int // Edge weights notified by EdgeWeightChanged
+	openList  *nodeQueue
+}
+
+// NewPlanner creates a Planner looking for the shortest path from startNode to endNode in graph.
+// No search is run until Path is called.
+func NewPlanner(graph dijkstrastructs.GraphObject, startNode, endNode string) *Planner {
+	p := &Planner{
+		graph:     graph,
+		startNode: startNode,
+		endNode:   endNode,
+		g:         make(map[string]cost),
+		rhs:       make(map[string]cost),
+		weights:   make(map[string]map[string]int),
+		openList:  newNodeQueue(),
+	}
+	p.rhs[startNode] = cost{}
+	p.openList.update(startNode, cost{})
+	return p
+}
+
+// EdgeWeightChanged notifies the planner that the edge going from u to v now has weight w.
+// The notified weight takes precedence over the one returned by the graph object; the planner must also be notified
+// of edges added to or removed from the graph, in which case w is only used if the edge is still listed by the graph.
+func (p *Planner) EdgeWeightChanged(u, v string, w int) {
+	if _, ok := p.weights[u]; !ok {
+		p.weights[u] = make(map[string]int)
+	}
+	p.weights[u][v] = w
+	p.updateNode(v)
+}
+
+// Path returns the current shortest path from the start node to the end node,
+// repairing the search state after the edge changes notified since the last call.
+func (p *Planner) Path() (dijkstrapath.DijkstraPath, bool) {
+	p.computeShortestPath()
+	if p.value(p.g, p.endNode) == unreached {
+		return dijkstrapath.DijkstraPath{}, false
+	}
+
+	// backtrack from the end node following the predecessors on a shortest path
+	nodes := []string{p.endNode}
+	seen := map[string]bool{p.endNode: true}
+	for n := p.endNode; n != p.startNode; {
+		gn := p.g[n]
+		next := ""
+		for _, c := range p.graph.PredecessorsFromNode(n) {
+			gp := p.value(p.g, c.Destination)
+			if !seen[c.Destination] && gp != unreached && gp.add(p.edgeWeight(c.Destination, n, c.Weight)) == gn {
+				next = c.Destination
+				break
+			}
+		}
+		if next == "" {
+			return dijkstrapath.DijkstraPath{}, false
+		}
+		nodes = append(nodes, next)
+		seen[next] = true
+		n = next
+	}
+
+	var parent *dijkstrastructs.DijkstraCandidate
+	for i := len(nodes) - 1; i >= 0; i-- {
+		parent = &dijkstrastructs.DijkstraCandidate{Node: nodes[i], Parent: parent, Weight: p.g[nodes[i]].weight}
+	}
+	cs := dijkstrastructs.CandidateSolution{
+		Length:        parent.Weight,
+		ForwCandidate: parent,
+		BackCandidate: &dijkstrastructs.DijkstraCandidate{Node: p.endNode},
+	}
+	return dijkstrapath.ConvertToDijkstraPath(cs, p.startNode, p.endNode), true
+}
+
+// Distance returns the weight of the best path from the start node to node found by the last call to Path.
+func (p *Planner) Distance(node string) (int, bool) {
+	c := p.value(p.g, node)
+	return c.weight, c != unreached
+}
+
+func (p *Planner) computeShortestPath() {
+	for p.openList.Len() > 0 {
+		node, key := p.openList.top()
+		if !key.less(p.key(p.endNode)) && p.value(p.rhs, p.endNode) == p.value(p.g, p.endNode) {
+			return
+		}
+		heap.Pop(p.openList)
+		if p.value(p.rhs, node).less(p.value(p.g, node)) {
+			// overconsistent: settle the node
+			p.g[node] = p.rhs[node]
+		} else {
+			// underconsistent: invalidate the node and fix it later
+			delete(p.g, node)
+			p.updateNode(node)
+		}
+		for _, s := range p.graph.SuccessorsForNode(node) {
+			p.updateNode(s.Destination)
+		}
+	}
+}
+
+// updateNode recomputes the lookahead value of node and its position in the open list.
+func (p *Planner) updateNode(node string) {
+	if node != p.startNode {
+		best := unreached
+		for _, c := range p.graph.PredecessorsFromNode(node) {
+			gp := p.value(p.g, c.Destination)
+			if gp == unreached {
+				continue
+			}
+			if w := gp.add(p.edgeWeight(c.Destination, node, c.Weight)); w.less(best) {
+				best = w
+			}
+		}
+		if best == unreached {
+			delete(p.rhs, node)
+		} else {
+			p.rhs[node] = best
+		}
+	}
+	if p.value(p.g, node) != p.value(p.rhs, node) {
+		p.openList.update(node, p.key(node))
+	} else {
+		p.openList.remove(node)
+	}
+}
+
+func (p *Planner) key(node string) cost {
+	g, rhs := p.value(p.g, node), p.value(p.rhs, node)
+	if g.less(rhs) {
+		return g
+	}
+	return rhs
+}
+
+func (p *Planner) value(m map[string]cost, node string) cost {
+	if v, ok := m[node]; ok {
+		return v
+	}
+	return unreached
+}
+
+func (p *Planner) edgeWeight(u, v string, w int) int {
+	if nw, ok := p.weights[u][v]; ok {
+		return nw
+	}
+	return w
+}
+
+// cost is the weight of a path followed by its number of edges, compared lexicographically.
+// Counting the edges makes every edge cost positive even when its weight is zero: with zero weight edges alone,
+// the nodes of a zero weight cycle could keep supporting each other after the edge leading into the cycle got heavier.
+type cost struct {
+	weight int
+	hops   int
+}
+
+func (c cost) less(o cost) bool {
+	if c.weight != o.weight {
+		return c.weight < o.weight
+	}
+	return c.hops < o.hops
+}
+
+func (c cost) add(w int) cost {
+	return cost{c.weight + w, c.hops + 1}
+}
+
+// nodeQueue is an indexed heap of nodes, allowing to update or remove the key of a queued node.
+type nodeQueue struct {
+	nodes []string
+	keys  map[string]cost
+	index map[string]int
+}
+
+func newNodeQueue() *nodeQueue {
+	return &nodeQueue{make([]string, 0), make(map[string]cost), make(map[string]int)}
+}
+
+func (pq *nodeQueue) Len() int {
+	return len(pq.nodes)
+}
+
+func (pq *nodeQueue) Less(i, j int) bool {
+	return pq.keys[pq.nodes[i]].less(pq.keys[pq.nodes[j]])
+}
+
+func (pq *nodeQueue) Swap(i, j int) {
+	pq.nodes[i], pq.nodes[j] = pq.nodes[j], pq.nodes[i]
+	pq.index[pq.nodes[i]] = i
+	pq.index[pq.nodes[j]] = j
+}
+
+func (pq *nodeQueue) Push(x interface{}) {
+	node := x.(string)
+	pq.index[node] = len(pq.nodes)
+	pq.nodes = append(pq.nodes, node)
+}
+
+func (pq *nodeQueue) Pop() interface{} {
+	n := len(pq.nodes)
+	node := pq.nodes[n-1]
+	pq.nodes = pq.nodes[0 : n-1]
+	delete(pq.index, node)
+	delete(pq.keys, node)
+	return node
+}
+
+func (pq *nodeQueue) top() (string, cost) {
+	return pq.nodes[0], pq.keys[pq.nodes[0]]
+}
+
+func (pq *nodeQueue) update(node string, key cost) {
+	pq.keys[node] = key
+	if i, ok := pq.index[node]; ok {
+		heap.Fix(pq, i)
+	} else {
+		heap.Push(pq, node)
+	}
+}
+
+func (pq *nodeQueue) remove(node string) {
+	if i, ok := pq.index[node]; ok {
+		heap.Remove(pq, i)
+	}
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2013 Alessandro Frossi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lpastar
+
+import (
+	"github.com/kirves/godijkstra/common/path"
+	"github.com/kirves/godijkstra/common/structs"
+	"github.com/kirves/godijkstra/dijkstra"
+	"github.com/kirves/godijkstra/graphtest"
+	"math/rand"
+	"testing"
+)
+
+func newGraph() *graphtest.Graph {
+	graph := graphtest.NewGraph()
+	graph.AddEdge("S", "A", "", 2)
+	graph.AddEdge("S", "B", "", 5)
+	graph.AddEdge("A", "B", "", 1)
+	graph.AddEdge("A", "C", "", 6)
+	graph.AddEdge("B", "C", "", 2)
+	graph.AddEdge("B", "D", "", 7)
+	graph.AddEdge("C", "D", "", 1)
+	graph.AddEdge("D", "T", "", 2)
+	graph.AddEdge("C", "T", "", 8)
+	return graph
+}
+
+func checkPath(t *testing.T, graph *graphtest.Graph, path dijkstrapath.DijkstraPath, valid bool) {
+	exp, expValid := dijkstra.Dijkstra(graph, "S", "T", dijkstrastructs.EmptyUnusableEdgeMap())
+	if valid != expValid {
+		t.Fatalf("Validity error: expected %t, got %t.", expValid, valid)
+	}
+	if !valid {
+		return
+	}
+	if path.Weight != exp.Weight {
+		t.Fatalf("Wrong path weight:\nExpected: %d\nGot: %d\n", exp.Weight, path.Weight)
+	}
+	if path.Path[0].Node != "S" || path.LastNode().Node != "T" {
+		t.Fatalf("Wrong path: %v", path.Path)
+	}
+	for i := 1; i < len(path.Path); i++ {
+		w := graph.EdgeWeight(path.Path[i-1].Node, path.Path[i].Node)
+		if path.Path[i].Weight-path.Path[i-1].Weight != w {
+			t.Fatalf("Inconsistent path weights: %v", path.Path)
+		}
+	}
+}
+
+func TestInitialPath(t *testing.T) {
+	graph := newGraph()
+	p := NewPlanner(graph, "S", "T")
+	path, valid := p.Path()
+	checkPath(t, graph, path, valid)
+	if path.Weight != 8 {
+		t.Fatalf("Wrong path weight:\nExpected: %d\nGot: %d\n", 8, path.Weight)
+	}
+}
+
+func TestEdgeWeightChanged(t *testing.T) {
+	graph := newGraph()
+	p := NewPlanner(graph, "S", "T")
+	p.Path()
+
+	updates := []struct {
+		u, v string
+		w    int
+	}{
+		{"C", "D", 10},
+		{"B", "C", 1},
+		{"S", "A", 20},
+		{"C", "D", 0},
+		{"D", "T", 15},
+	}
+	for _, u := range updates {
+		graph.RemoveEdges(u.u, u.v)
+		graph.AddEdge(u.u, u.v, "", u.w)
+		p.EdgeWeightChanged(u.u, u.v, u.w)
+		path, valid := p.Path()
+		checkPath(t, graph, path, valid)
+	}
+}
+
+func TestEdgeRemoved(t *testing.T) {
+	graph := newGraph()
+	p := NewPlanner(graph, "S", "T")
+	p.Path()
+
+	for _, e := range [][]string{{"D", "T"}, {"C", "T"}} {
+		graph.RemoveEdges(e[0], e[1])
+		p.EdgeWeightChanged(e[0], e[1], 0)
+		path, valid := p.Path()
+		checkPath(t, graph, path, valid)
+	}
+	if _, valid := p.Path(); valid {
+		t.Fatal("A path was found in an unconnected graph.")
+	}
+}
+
+func TestRandomUpdates(t *testing.T) {
+	for seed := int64(0); seed < 30; seed++ {
+		graph := graphtest.ErdosRenyi(15, 0.25, 6, seed)
+		s, e := graphtest.NodeName(0), graphtest.NodeName(14)
+		var edges [][2]string
+		for _, n := range graph.Nodes() {
+			for _, c := range graph.SuccessorsForNode(n) {
+				edges = append(edges, [2]string{n, c.Destination})
+			}
+		}
+		if len(edges) == 0 {
+			continue
+		}
+		p := NewPlanner(graph, s, e)
+		r := rand.New(rand.NewSource(seed))
+		for i := 0; i < 40; i++ {
+			// weights include 0, which makes ties between the end node and inconsistent nodes, and zero weight cycles
+			u := edges[r.Intn(len(edges))]
+			w := r.Intn(7)
+			graph.RemoveEdges(u[0], u[1])
+			graph.AddEdge(u[0], u[1], "", w)
+			p.EdgeWeightChanged(u[0], u[1], w)
+
+			exp, expValid := dijkstra.Dijkstra(graph, s, e, dijkstrastructs.EmptyUnusableEdgeMap())
+			path, valid := p.Path()
+			if valid != expValid || (valid && path.Weight != exp.Weight) {
+				t.Fatalf("Wrong path after %d updates (seed %d):\nExpected: %d (%t)\nGot: %d (%t)\n", i+1, seed, exp.Weight, expValid, path.Weight, valid)
+			}
+		}
+	}
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2013 Alessandro Frossi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package Pareto implements a multi-objective search returning the Pareto-optimal paths between two nodes.
+//
+// The search works on graph objects implementing the dijkstrastructs.MultiCriteriaGraph interface, whose edges have one
+// weight for each criterion (e.g. time and cost). A path is Pareto-optimal if no other path is at least as good in every
+// criterion and better in one of them; the set of such paths is the Pareto front.
+// The algorithm is a multi-label extension of Dijkstra algorithm: labels are extracted in lexicographic order of their
+// objective vectors and a label is discarded when it is dominated by a label already settled at the same node,
+// or by a path already found to the destination.
+package pareto
+
+import (
+	"container/heap"
+	"github.com/kirves/godijkstra/common/path"
+	"github.com/kirves/godijkstra/common/structs"
+)
+
+// Options defines the optional parameters of the Pareto search.
+type Options struct {
+	// Epsilon enables epsilon-dominance on the front: a path is discarded if another one is within a factor
+	// 1+Epsilon of it in every criterion. Every Pareto-optimal path is then approximated within that factor by a
+	// returned path, and the size of the front is bounded. The zero value returns the exact front.
+	Epsilon float64
+}
+
+// label is a partial path reaching a node, together with its objective vector.
+type label struct {
+	candidate  *dijkstrastructs.DijkstraCandidate // Last step of the partial path
+	objectives []int                              // Weight of the partial path for each criterion
+}
+
+// ParetoPaths returns the Pareto front of the paths going from startNode to endNode within the provided graph,
+// sorted in lexicographic order of their objective vectors, which are returned as well.
+// criteria is the number of criteria to optimize: edge weights beyond it are ignored, missing ones count as 0.
+// The weights of the returned DijkstraPaths are computed on the first criterion. No path is returned if criteria is less than 1.
+func ParetoPaths(graph dijkstrastructs.MultiCriteriaGraph, startNode, endNode string, criteria int, opts Options) ([]dijkstrapath.DijkstraPath, [][]int) {
+	if criteria < 1 {
+		return nil, nil
+	}
+	paths := make([]dijkstrapath.DijkstraPath, 0)
+	objectives := make([][]int, 0)
+	settled := make(map[string][]label)
+	front := make([]label, 0)
+
+	openList := &labelQueue{}
+	heap.Init(openList)
+	heap.Push(openList, label{&dijkstrastructs.DijkstraCandidate{Node: startNode}, make([]int, criteria)})
+
+	for openList.Len() > 0 {
+		l := heap.Pop(openList).(label)
+		node := l.candidate.Node
+
+		if isDominated(l, settled[node], 0) || isDominated(l, front, opts.Epsilon) {
+			continue
+		}
+		settled[node] = append(settled[node], l)
+
+		if node == endNode {
+			front = append(front, l)
+			cs := dijkstrastructs.CandidateSolution{
+				Length:        l.candidate.Weight,
+				ForwCandidate: l.candidate,
+				BackCandidate: &dijkstrastructs.DijkstraCandidate{Node: endNode},
+			}
+			paths = append(paths, dijkstrapath.ConvertToDijkstraPath(cs, startNode, endNode))
+			objectives = append(objectives, l.objectives)
+			continue
+		}
+
+		for _, s := range graph.MultiCriteriaSuccessorsForNode(node) {
+			newObjectives := make([]int, criteria)
+			for i := range newObjectives {
+				newObjectives[i] = l.objectives[i]
+				if i < len(s.Weights) {
+					newObjectives[i] += s.Weights[i]
+				}
+			}
+			newLabel := label{
+				&dijkstrastructs.DijkstraCandidate{Node: s.Destination, Parent: l.candidate, Weight: newObjectives[0]},
+				newObjectives,
+			}
+			if isDominated(newLabel, settled[s.Destination], 0) || isDominated(newLabel, front, opts.Epsilon) {
+				continue
+			}
+			heap.Push(openList, newLabel)
+		}
+	}
+	return paths, objectives
+}
+
+// isDominated states if one of labels epsilon-dominates l.
+func isDominated(l label, labels []label, epsilon float64) bool {
+	for _, o := range labels {
+		if dominates(o.objectives, l.objectives, epsilon) {
+			return true
+		}
+	}
+	return false
+}
+
+// dominates states if a is within a factor 1+epsilon of b in every criterion.
+func dominates(a, b []int, epsilon float64) bool {
+	for i := range a {
+		if epsilon == 0 {
+			if a[i] > b[i] {
+				return false
+			}
+		} else if float64(a[i]) > (1+epsilon)*float64(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// labelQueue is a heap of labels sorted in lexicographic order of their objective vectors.
+type labelQueue []label
+
+func (pq labelQueue) Len() int {
+	return len(pq)
+}
+
+func (pq labelQueue) Less(i, j int) bool {
+	for k := range pq[i].objectives {
+		if pq[i].objectives[k] != pq[j].objectives[k] {
+			return pq[i].objectives[k] < pq[j].objectives[k]
+		}
+	}
+	return false
+}
+
+func (pq labelQueue) Swap(i, j int) {
+	pq[i], pq[j] = pq[j], pq[i]
+}
+
+func (pq *labelQueue) Push(x interface{}) {
+	*pq = append(*pq, x.(label))
+}
+
+func (pq *labelQueue) Pop() interface{} {
+	old := *pq
+	n := len(old)
+	x := old[n-1]
+	*pq = old[0 : n-1]
+	return x
+}
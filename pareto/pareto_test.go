@@ -0,0 +1,98 @@
+/*
+Copyright 2013 Alessandro Frossi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pareto
+
+import (
+	"testing"
+)
+
+var (
+	graph *testGraph
+)
+
+func init() {
+	// criteria: time, cost
+	graph = newTestGraph()
+	graph.addEdge("S", "A", 1, 10)
+	graph.addEdge("A", "T", 1, 10)
+	graph.addEdge("S", "B", 2, 5)
+	graph.addEdge("B", "T", 2, 5)
+	graph.addEdge("S", "C", 5, 1)
+	graph.addEdge("C", "T", 5, 1)
+	graph.addEdge("S", "D", 3, 9)
+	graph.addEdge("D", "T", 3, 9)
+	graph.addEdge("A", "B", 1, 1)
+}
+
+func TestParetoFront(t *testing.T) {
+	paths, objectives := ParetoPaths(graph, "S", "T", 2, Options{})
+	// S-A-B-T (4, 16) and S-D-T (6, 18) are dominated by S-B-T (4, 10)
+	expPaths := [][]string{
+		{"S", "A", "T"},
+		{"S", "B", "T"},
+		{"S", "C", "T"},
+	}
+	expObjectives := [][]int{{2, 20}, {4, 10}, {10, 2}}
+
+	if len(paths) != len(expPaths) {
+		t.Fatalf("Wrong front size:\nExpected: %d\nGot: %d (%v)\n", len(expPaths), len(paths), objectives)
+	}
+	for k, p := range paths {
+		if len(p.Path) != len(expPaths[k]) {
+			t.Fatalf("Wrong path (%d): %v", k, p.Path)
+		}
+		for i, v := range p.Path {
+			if v.Node != expPaths[k][i] {
+				t.Fatalf("Wrong path (%d): %v", k, p.Path)
+			}
+		}
+		for i := range expObjectives[k] {
+			if objectives[k][i] != expObjectives[k][i] {
+				t.Fatalf("Wrong objectives (%d):\nExpected: %v\nGot: %v\n", k, expObjectives[k], objectives[k])
+			}
+		}
+		if p.Weight != expObjectives[k][0] {
+			t.Fatalf("Wrong path weight (%d):\nExpected: %d\nGot: %d\n", k, expObjectives[k][0], p.Weight)
+		}
+	}
+}
+
+func TestEpsilonDominance(t *testing.T) {
+	exact, _ := ParetoPaths(graph, "S", "T", 2, Options{})
+	approx, objectives := ParetoPaths(graph, "S", "T", 2, Options{Epsilon: 1})
+	if len(approx) == 0 || len(approx) >= len(exact) {
+		t.Fatalf("Epsilon-dominance did not reduce the front: %v", objectives)
+	}
+	if objectives[0][0] != 2 {
+		t.Fatalf("Fastest path missing from the approximated front: %v", objectives)
+	}
+}
+
+func TestUnreachable(t *testing.T) {
+	paths, _ := ParetoPaths(graph, "T", "S", 2, Options{})
+	if len(paths) != 0 {
+		t.Fatal("A path was found in an unconnected graph.")
+	}
+}
+
+func TestNoCriteria(t *testing.T) {
+	for _, criteria := range []int{0, -1} {
+		if paths, objectives := ParetoPaths(graph, "S", "T", criteria, Options{}); paths != nil || objectives != nil {
+			t.Fatalf("Paths found without criteria (%d): %v", criteria, objectives)
+		}
+	}
+}
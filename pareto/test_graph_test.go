@@ -0,0 +1,37 @@
+/*
+Copyright 2013 Alessandro Frossi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pareto
+
+import (
+	"github.com/kirves/godijkstra/common/structs"
+)
+
+type testGraph struct {
+	edges map[string][]dijkstrastructs.MultiCriteriaConnection
+}
+
+func newTestGraph() *testGraph {
+	return &testGraph{make(map[string][]dijkstrastructs.MultiCriteriaConnection)}
+}
+
+func (t *testGraph) addEdge(n1, n2 string, weights ...int) {
+	t.edges[n1] = append(t.edges[n1], dijkstrastructs.MultiCriteriaConnection{Destination: n2, Weights: weights})
+}
+
+func (t *testGraph) MultiCriteriaSuccessorsForNode(node string) []dijkstrastructs.MultiCriteriaConnection {
+	return t.edges[node]
+}
@@ -0,0 +1,317 @@
+/*
+Copyright 2013 Alessandro Frossi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package Tour finds the order in which to visit a set of stops in a graph, solving the traveling salesman problem
+// on top of the shortest paths between the stops.
+//
+// The distances between every pair of stops are computed with one backward Dijkstra search per stop and stored
+// in a DistanceMatrix. Small instances are solved exactly by dynamic programming (Held-Karp algorithm), larger ones by a nearest neighbour
+// tour improved with 2-opt and Or-opt moves. Since the graph is directed, the distance matrix can be asymmetric
+// and every move is evaluated on the actual direction of travel.
+package tour
+
+import (
+	"github.com/kirves/godijkstra/common/path"
+	"github.com/kirves/godijkstra/common/structs"
+	"github.com/kirves/godijkstra/dijkstra"
+)
+
+const (
+	DefaultExactLimit = 12 // Largest number of stops solved exactly when Options.ExactLimit is not set
+	MaxExactLimit     = 16 // Largest accepted Options.ExactLimit: the exact solution takes O(2^n·n) memory
+)
+
+// unreachable is the cost given to a leg between two stops that are not connected.
+// It is large enough to make any feasible tour cheaper, yet small enough not to overflow a tour cost.
+const unreachable int64 = 1 << 40
+
+// Options defines the parameters of the tour search.
+type Options struct {
+	Closed     bool // Return to the first stop at the end of the tour
+	ExactLimit int  // Largest number of stops solved exactly (DefaultExactLimit if 0, at most MaxExactLimit)
+}
+
+// Tour is an ordered visit of a set of stops.
+type Tour struct {
+	Order []string                  // Stops in visiting order, starting from the first stop
+	Path  dijkstrapath.DijkstraPath // Complete path through the stops (back to the first one for closed tours)
+}
+
+// DistanceMatrix holds the shortest paths between every ordered pair of stops.
+type DistanceMatrix struct {
+	Stops []string
+	paths [][]dijkstrapath.DijkstraPath
+	valid [][]bool
+}
+
+// NewDistanceMatrix computes the shortest paths between every ordered pair of stops within the provided graph,
+// running a single backward search towards each stop.
+func NewDistanceMatrix(graph dijkstrastructs.GraphObject, stops []string) *DistanceMatrix {
+	m := &DistanceMatrix{stops, make([][]dijkstrapath.DijkstraPath, len(stops)), make([][]bool, len(stops))}
+	for i := range stops {
+		m.paths[i] = make([]dijkstrapath.DijkstraPath, len(stops))
+		m.valid[i] = make([]bool, len(stops))
+	}
+	for j, to := range stops {
+		rt := dijkstra.ShortestPathsTo(graph, to, dijkstrastructs.EmptyUnusableEdgeMap())
+		for i, from := range stops {
+			m.paths[i][j], m.valid[i][j] = rt.PathFrom(from)
+		}
+	}
+	return m
+}
+
+// Distance returns the weight of the shortest path from the i-th to the j-th stop.
+// The second return value is false if the j-th stop cannot be reached from the i-th.
+func (m *DistanceMatrix) Distance(i, j int) (int, bool) {
+	return m.paths[i][j].Weight, m.valid[i][j]
+}
+
+// Solve returns the cheapest tour visiting all the stops within the provided graph, starting from the first one.
+// The second return value is false if no tour can visit every stop.
+func Solve(graph dijkstrastructs.GraphObject, stops []string, opts Options) (Tour, bool) {
+	if len(stops) == 0 {
+		return Tour{}, false
+	}
+	return NewDistanceMatrix(graph, stops).Solve(opts)
+}
+
+// Solve returns the cheapest tour visiting all the stops of the matrix, starting from the first one.
+// The second return value is false if no tour can visit every stop.
+func (m *DistanceMatrix) Solve(opts Options) (Tour, bool) {
+	n := len(m.Stops)
+	if n == 0 {
+		return Tour{}, false
+	}
+	limit := opts.ExactLimit
+	if limit <= 0 {
+		limit = DefaultExactLimit
+	} else if limit > MaxExactLimit {
+		limit = MaxExactLimit
+	}
+
+	var order []int
+	if n <= limit {
+		order = m.heldKarp(opts.Closed)
+	} else {
+		order = m.nearestNeighbour()
+		m.improve(order, opts.Closed)
+	}
+	if m.cost(order, opts.Closed) >= unreachable {
+		return Tour{}, false
+	}
+	return m.expand(order, opts.Closed), true
+}
+
+func (m *DistanceMatrix) weight(i, j int) int64 {
+	if !m.valid[i][j] {
+		return unreachable
+	}
+	return int64(m.paths[i][j].Weight)
+}
+
+// cost returns the weight of the tour visiting the stops in order.
+func (m *DistanceMatrix) cost(order []int, closed bool) int64 {
+	var ret int64
+	for i := 1; i < len(order); i++ {
+		ret += m.weight(order[i-1], order[i])
+	}
+	if closed && len(order) > 1 {
+		ret += m.weight(order[len(order)-1], order[0])
+	}
+	return ret
+}
+
+// heldKarp solves the instance exactly: best[set][j] is the weight of the cheapest path
+// starting from stop 0, visiting the stops in set and ending in stop j.
+func (m *DistanceMatrix) heldKarp(closed bool) []int {
+	n := len(m.Stops)
+	if n == 1 {
+		return []int{0}
+	}
+	full := 1<<uint(n) - 1
+	best := make([][]int64, full+1)
+	parent := make([][]int, full+1)
+	for set := range best {
+		best[set] = make([]int64, n)
+		parent[set] = make([]int, n)
+		for j := range best[set] {
+			best[set][j] = -1
+		}
+	}
+	best[1][0] = 0
+
+	for set := 1; set <= full; set += 2 {
+		for j := 0; j < n; j++ {
+			if best[set][j] < 0 {
+				continue
+			}
+			for k := 1; k < n; k++ {
+				if set&(1<<uint(k)) != 0 {
+					continue
+				}
+				next := set | 1<<uint(k)
+				w := best[set][j] + m.weight(j, k)
+				if best[next][k] < 0 || w < best[next][k] {
+					best[next][k] = w
+					parent[next][k] = j
+				}
+			}
+		}
+	}
+
+	last := -1
+	var lastWeight int64
+	for j := 1; j < n; j++ {
+		w := best[full][j]
+		if closed {
+			w += m.weight(j, 0)
+		}
+		if last < 0 || w < lastWeight {
+			last, lastWeight = j, w
+		}
+	}
+
+	order := make([]int, n)
+	for i, set := n-1, full; i >= 0; i-- {
+		order[i] = last
+		set, last = set&^(1<<uint(last)), parent[set][last]
+	}
+	return order
+}
+
+// nearestNeighbour builds a tour starting from stop 0 and moving each time to the closest stop not yet visited.
+func (m *DistanceMatrix) nearestNeighbour() []int {
+	n := len(m.Stops)
+	visited := make([]bool, n)
+	order := []int{0}
+	visited[0] = true
+	for len(order) < n {
+		cur := order[len(order)-1]
+		next := -1
+		for j := 0; j < n; j++ {
+			if visited[j] {
+				continue
+			}
+			if next < 0 || m.weight(cur, j) < m.weight(cur, next) {
+				next = j
+			}
+		}
+		visited[next] = true
+		order = append(order, next)
+	}
+	return order
+}
+
+// improve applies 2-opt and Or-opt moves to order until no move makes the tour cheaper.
+// The first stop is never moved.
+func (m *DistanceMatrix) improve(order []int, closed bool) {
+	best := m.cost(order, closed)
+	for improved := true; improved; {
+		improved = false
+
+		// 2-opt: reverse the stops between i and j.
+		// fwd and rev are the weights of the segment in both directions, which differ in directed graphs.
+		for i := 1; i < len(order)-1; i++ {
+			var fwd, rev int64
+			for j := i + 1; j < len(order); j++ {
+				fwd += m.weight(order[j-1], order[j])
+				rev += m.weight(order[j], order[j-1])
+				delta := rev - fwd + m.weight(order[i-1], order[j]) - m.weight(order[i-1], order[i])
+				if next := successor(order, j, closed); next >= 0 {
+					delta += m.weight(order[i], next) - m.weight(order[j], next)
+				}
+				if delta < 0 {
+					reverse(order[i : j+1])
+					fwd, rev = rev, fwd
+					best += delta
+					improved = true
+				}
+			}
+		}
+
+		// Or-opt: move a segment of up to 3 stops to another position
+		for length := 1; length <= 3; length++ {
+			for i := 1; i+length <= len(order); i++ {
+				for j := 1; j+length <= len(order); j++ {
+					if j == i {
+						continue
+					}
+					moved := moveSegment(order, i, length, j)
+					if c := m.cost(moved, closed); c < best {
+						copy(order, moved)
+						best = c
+						improved = true
+					}
+				}
+			}
+		}
+	}
+}
+
+// expand builds the tour visiting the stops in order, merging the shortest paths between them.
+func (m *DistanceMatrix) expand(order []int, closed bool) Tour {
+	ret := Tour{Order: make([]string, len(order))}
+	for i, s := range order {
+		ret.Order[i] = m.Stops[s]
+	}
+	if closed {
+		order = append(order, order[0])
+	}
+	if len(order) == 1 {
+		ret.Path = m.paths[order[0]][order[0]]
+		return ret
+	}
+	ret.Path = m.paths[order[0]][order[1]]
+	for i := 2; i < len(order); i++ {
+		ret.Path = ret.Path.MergeWith(m.paths[order[i-1]][order[i]])
+	}
+	ret.Path.EndNode = m.Stops[order[len(order)-1]]
+	return ret
+}
+
+// successor returns the stop visited after the j-th one, or -1 at the end of an open tour.
+func successor(order []int, j int, closed bool) int {
+	switch {
+	case j+1 < len(order):
+		return order[j+1]
+	case closed:
+		return order[0]
+	}
+	return -1
+}
+
+func reverse(s []int) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
+
+// moveSegment returns a copy of order where the length stops starting at i are moved to start at position j
+// of the remaining stops.
+func moveSegment(order []int, i, length, j int) []int {
+	rest := make([]int, 0, len(order))
+	rest = append(rest, order[:i]...)
+	rest = append(rest, order[i+length:]...)
+	if j > len(rest) {
+		j = len(rest)
+	}
+	ret := make([]int, 0, len(order))
+	ret = append(ret, rest[:j]...)
+	ret = append(ret, order[i:i+length]...)
+	ret = append(ret, rest[j:]...)
+	return ret
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2013 Alessandro Frossi
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tour
+
+import (
+	"fmt"
+	"github.com/kirves/godijkstra/graphtest"
+	"testing"
+)
+
+var (
+	graph *graphtest.Graph
+	stops []string
+)
+
+func init() {
+	// two-way ring of 10 nodes with unit weights and a few expensive chords
+	graph = graphtest.NewGraph()
+	for i := 0; i < 10; i++ {
+		a, b := fmt.Sprintf("N%d", i), fmt.Sprintf("N%d", (i+1)%10)
+		graph.AddEdge(a, b, "", 1)
+		graph.AddEdge(b, a, "", 1)
+	}
+	graph.AddEdge("N0", "N5", "", 7)
+	graph.AddEdge("N3", "N8", "", 7)
+	graph.AddNode("U")
+	stops = []string{"N0", "N6", "N2", "N8", "N4"}
+}
+
+func checkTour(t *testing.T, tour Tour, closed bool, expWeight int) {
+	if tour.Order[0] != stops[0] || len(tour.Order) != len(stops) {
+		t.Fatalf("Wrong tour order: %v", tour.Order)
+	}
+	if tour.Path.Weight != expWeight {
+		t.Fatalf("Wrong tour weight:\nExpected: %d\nGot: %d (%v)\n", expWeight, tour.Path.Weight, tour.Order)
+	}
+	next := 0
+	for i, e := range tour.Path.Path {
+		if next < len(tour.Order) && e.Node == tour.Order[next] {
+			next++
+		}
+		if i > 0 && e.Weight-tour.Path.Path[i-1].Weight != graph.EdgeWeight(tour.Path.Path[i-1].Node, e.Node) {
+			t.Fatalf("Inconsistent path weights: %v", tour.Path.Path)
+		}
+	}
+	if next != len(tour.Order) {
+		t.Fatalf("The path does not visit the stops in order: %v", tour.Path.Path)
+	}
+	if closed && tour.Path.LastNode().Node != stops[0] {
+		t.Fatalf("Closed tour does not end in the first stop: %v", tour.Path.Path)
+	}
+}
+
+func TestExact(t *testing.T) {
+	open, valid := Solve(graph, stops, Options{})
+	if !valid {
+		t.Fatal("Validity error.")
+	}
+	checkTour(t, open, false, 8)
+
+	closed, valid := Solve(graph, stops, Options{Closed: true})
+	if !valid {
+		t.Fatal("Validity error.")
+	}
+	checkTour(t, closed, true, 10)
+}
+
+func TestHeuristic(t *testing.T) {
+	open, valid := Solve(graph, stops, Options{ExactLimit: 1})
+	if !valid {
+		t.Fatal("Validity error.")
+	}
+	checkTour(t, open, false, 8)
+
+	closed, valid := Solve(graph, stops, Options{Closed: true, ExactLimit: 1})
+	if !valid {
+		t.Fatal("Validity error.")
+	}
+	checkTour(t, closed, true, 10)
+}
+
+func TestUnreachableStop(t *testing.T) {
+	for _, limit := range []int{0, 1} {
+		if _, valid := Solve(graph, []string{"N0", "U", "N4"}, Options{ExactLimit: limit}); valid {
+			t.Fatal("A tour was found through an unreachable stop.")
+		}
+	}
+}
+
+func TestLargeExactLimit(t *testing.T) {
+	// an exact solution of 24 stops would need gigabytes: the limit must be lowered to MaxExactLimit
+	g := graphtest.Grid(5, 6, 10, 1)
+	stops := g.Nodes()[:24]
+	m := NewDistanceMatrix(g, stops)
+	for _, closed := range []bool{false, true} {
+		tour, valid := m.Solve(Options{Closed: closed, ExactLimit: 64})
+		if !valid || len(tour.Order) != len(stops) {
+			t.Fatalf("Wrong tour: %v", tour.Order)
+		}
+		order := make([]int, len(tour.Order))
+		for i, s := range tour.Order {
+			for j := range stops {
+				if stops[j] == s {
+					order[i] = j
+				}
+			}
+		}
+		if c := m.cost(order, closed); int64(tour.Path.Weight) != c {
+			t.Fatalf("Inconsistent tour weight: %d, the matrix gives %d", tour.Path.Weight, c)
+		}
+	}
+}
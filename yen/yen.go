@@ -28,18 +28,65 @@ import (
 	"github.com/kirves/godijkstra/common/structs"
 )
 
+// HopLimitedSearchFunc is a search function returning the shortest path that uses at most the given number of edges,
+// such as dijkstra.HopLimitedDijkstra.
+type HopLimitedSearchFunc func(dijkstrastructs.GraphObject, string, string, dijkstrastructs.UnusableEdgeMap, int) (dijkstrapath.DijkstraPath, bool)
+
+// Yen returns the k shortest paths going from startNode to endNode within the provided graph,
+// using searchFunc to compute each deviation.
+// Paths with the same weight are returned by increasing number of nodes, then in lexicographic order of their nodes;
+// the result is reproducible as long as searchFunc is, e.g. with dijkstra.SearchOptions.TieBreak set.
 func Yen(
 	graph dijkstrastructs.GraphObject,
 	startNode, endNode string,
 	k int,
 	searchFunc func(dijkstrastructs.GraphObject, string, string, dijkstrastructs.UnusableEdgeMap) (dijkstrapath.DijkstraPath, bool)) []dijkstrapath.DijkstraPath {
 
+	return kShortestPaths(graph, startNode, k, func(rp dijkstrapath.DijkstraPath, spurNode string, bannedEdges dijkstrastructs.UnusableEdgeMap) (dijkstrapath.DijkstraPath, bool) {
+		return searchFunc(graph, spurNode, endNode, bannedEdges)
+	})
+}
+
+// YenMaxHops returns the k shortest paths going from startNode to endNode within the provided graph
+// that use at most maxHops edges. Each deviation is computed by searchFunc, limited to the edges left
+// after its root path. A non-positive maxHops means no limit, as in dijkstra.HopLimitedDijkstra.
+func YenMaxHops(
+	graph dijkstrastructs.GraphObject,
+	startNode, endNode string,
+	k int,
+	maxHops int,
+	searchFunc HopLimitedSearchFunc) []dijkstrapath.DijkstraPath {
+
+	return kShortestPaths(graph, startNode, k, func(rp dijkstrapath.DijkstraPath, spurNode string, bannedEdges dijkstrastructs.UnusableEdgeMap) (dijkstrapath.DijkstraPath, bool) {
+		if maxHops <= 0 {
+			return searchFunc(graph, spurNode, endNode, bannedEdges, 0)
+		}
+		// the root path is empty for the first solution
+		hops := maxHops
+		if len(rp.Path) > 0 {
+			hops -= len(rp.Path) - 1
+		}
+		if hops <= 0 {
+			return dijkstrapath.DijkstraPath{}, false
+		}
+		return searchFunc(graph, spurNode, endNode, bannedEdges, hops)
+	})
+}
+
+// kShortestPaths implements the deviation algorithm. search computes the shortest path from spurNode
+// to the end node avoiding bannedEdges, to be appended to the root path rp (empty for the first solution).
+func kShortestPaths(
+	graph dijkstrastructs.GraphObject,
+	startNode string,
+	k int,
+	search func(rp dijkstrapath.DijkstraPath, spurNode string, bannedEdges dijkstrastructs.UnusableEdgeMap) (dijkstrapath.DijkstraPath, bool)) []dijkstrapath.DijkstraPath {
+
 	if k <= 0 {
 		return make([]dijkstrapath.DijkstraPath, 0)
 	}
 
 	// FIRST SOLUTION ========================
-	dp, valid := searchFunc(graph, startNode, endNode, dijkstrastructs.EmptyUnusableEdgeMap())
+	dp, valid := search(dijkstrapath.DijkstraPath{}, startNode, dijkstrastructs.EmptyUnusableEdgeMap())
 	if !valid {
 		return make([]dijkstrapath.DijkstraPath, 0)
 	}
@@ -48,7 +95,7 @@ func Yen(
 	finalList := make([]dijkstrapath.DijkstraPath, 0)
 	// foundPaths := make(map[string]interface{})
 
-	candidateHeap := &dijkstrapath.DijkstraPathQueue{}
+	candidateHeap := &dijkstrapath.OrderedDijkstraPathQueue{}
 	heap.Init(candidateHeap)
 	heap.Push(candidateHeap, dp)
 
@@ -56,6 +103,11 @@ func Yen(
 	for candidateHeap.Len() > 0 {
 		cdp = heap.Pop(candidateHeap).(dijkstrapath.DijkstraPath)
 
+		// the same deviation can be found from different root paths
+		if containsPath(finalList, cdp) {
+			continue
+		}
+
 		// add to finals and check if we're done
 		finalList = append(finalList, cdp)
 
@@ -68,17 +120,20 @@ func Yen(
 			for _, path := range finalList {
 				be := path.OutgoingEdgeForSubPath(rp)
 				if be != nil {
-					if _, ok := bannedEdges[be[0]]; !ok {
-						bannedEdges[be[0]] = make(map[string]interface{})
-					}
-					bannedEdges[be[0]][be[1]] = struct{}{}
+					bannedEdges.BanEdge(be[0], be[1], path.OutgoingEdgeIDForSubPath(rp))
+				}
+			}
+			// the deviation must not go back to the root path: no path can leave its nodes but the spur node
+			for _, e := range rp.Path[0 : len(rp.Path)-1] {
+				for _, c := range graph.SuccessorsForNode(e.Node) {
+					bannedEdges.Ban(e.Node, c.Destination)
 				}
 			}
 
 			// build start and end sets
 			// 3 cases
 			ln := rp.LastNode()
-			dp, valid = searchFunc(graph, ln.Node, endNode, bannedEdges)
+			dp, valid = search(rp, ln.Node, bannedEdges)
 			if !valid {
 				continue
 			}
@@ -90,3 +145,12 @@ func Yen(
 	}
 	return finalList
 }
+
+func containsPath(paths []dijkstrapath.DijkstraPath, p dijkstrapath.DijkstraPath) bool {
+	for _, v := range paths {
+		if v.IsEqual(p) {
+			return true
+		}
+	}
+	return false
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/kirves/godijkstra/common/path"
 	"github.com/kirves/godijkstra/common/structs"
 	"github.com/kirves/godijkstra/dijkstra"
+	"github.com/kirves/godijkstra/graphtest"
 	"testing"
 	"time"
 )
@@ -113,3 +114,155 @@ func TestTermination(t *testing.T) {
 		}
 	}
 }
+
+func TestMaxHops(t *testing.T) {
+	paths := YenMaxHops(graph, "S", "T", 4, 5, dijkstra.HopLimitedDijkstra)
+	expPath := [][]string{
+		[]string{"S", "A", "C", "G", "T"},
+		[]string{"S", "A", "C", "E", "G", "T"},
+	}
+	if len(paths) != len(expPath) {
+		t.Fatalf("Wrong number of paths:\nExpected: %d\nGot: %d\n", len(expPath), len(paths))
+	}
+	for k, p := range paths {
+		if len(p.Path) != len(expPath[k]) {
+			t.Fatalf("Wrong path (%d).\n", k)
+		}
+		for i, v := range p.Path {
+			if v.Node != expPath[k][i] {
+				t.Fatalf("Wrong path (%d).\n", k)
+			}
+		}
+	}
+}
+
+func TestMaxHopsLimit(t *testing.T) {
+	// the shortest path S-A-B-T has more edges than the limit
+	g := graphtest.NewGraph()
+	g.AddEdge("S", "A", "", 1)
+	g.AddEdge("A", "B", "", 1)
+	g.AddEdge("B", "T", "", 1)
+	g.AddEdge("S", "T", "", 10)
+	g.AddEdge("A", "T", "", 5)
+	for _, c := range []struct {
+		maxHops    int
+		expWeights []int
+	}{
+		{2, []int{6, 10}},
+		{1, []int{10}},
+		{0, []int{3, 6, 10}},
+		{-1, []int{3, 6, 10}},
+	} {
+		paths := YenMaxHops(g, "S", "T", 5, c.maxHops, dijkstra.HopLimitedDijkstra)
+		if len(paths) != len(c.expWeights) {
+			t.Fatalf("Wrong number of paths (max %d hops):\nExpected: %d\nGot: %d\n", c.maxHops, len(c.expWeights), len(paths))
+		}
+		for k, p := range paths {
+			if p.Weight != c.expWeights[k] || (c.maxHops > 0 && len(p.Path)-1 > c.maxHops) {
+				t.Fatalf("Wrong path (%d, max %d hops): %v", k, c.maxHops, p.Path)
+			}
+		}
+	}
+}
+
+func TestParallelEdges(t *testing.T) {
+	g := graphtest.NewGraph()
+	g.AddEdge("S", "A", "road", 1)
+	g.AddEdge("S", "A", "ferry", 4)
+	g.AddEdge("A", "T", "bridge", 1)
+	g.AddEdge("A", "T", "tunnel", 2)
+
+	expEdges := [][]string{
+		[]string{"road", "bridge"},
+		[]string{"road", "tunnel"},
+		[]string{"ferry", "bridge"},
+		[]string{"ferry", "tunnel"},
+	}
+	expWeights := []int{2, 3, 5, 6}
+	for _, searchType := range []int{dijkstra.VANILLA, dijkstra.BIDIR} {
+		paths := Yen(g, "S", "T", 5, dijkstra.SearchFunc(searchType, dijkstra.SearchOptions{}))
+		if len(paths) != len(expEdges) {
+			t.Fatalf("Wrong number of paths (%d):\nExpected: %d\nGot: %d\n", searchType, len(expEdges), len(paths))
+		}
+		for k, p := range paths {
+			if p.Weight != expWeights[k] {
+				t.Fatalf("Wrong path weight (%d, %d):\nExpected: %d\nGot: %d\n", searchType, k, expWeights[k], p.Weight)
+			}
+			for i, id := range expEdges[k] {
+				if p.Path[i+1].EdgeID != id {
+					t.Fatalf("Wrong path (%d, %d): %v\n", searchType, k, p.Path)
+				}
+			}
+		}
+	}
+}
+
+func TestCostProfile(t *testing.T) {
+	// avoid node C entirely
+	opts := dijkstra.SearchOptions{Cost: func(from string, c dijkstrastructs.Connection) (int, bool) {
+		return c.Weight, c.Destination != "C"
+	}}
+	for _, searchType := range []int{dijkstra.VANILLA, dijkstra.BIDIR} {
+		paths := Yen(graph, "S", "T", 3, dijkstra.SearchFunc(searchType, opts))
+		if len(paths) != 0 {
+			t.Fatalf("Found paths through a forbidden node: %v", paths)
+		}
+	}
+
+	// make the edge A -> C expensive
+	opts = dijkstra.SearchOptions{Cost: func(from string, c dijkstrastructs.Connection) (int, bool) {
+		if from == "A" && c.Destination == "C" {
+			return 10, true
+		}
+		return c.Weight, true
+	}}
+	paths := Yen(graph, "S", "T", 2, dijkstra.SearchFunc(dijkstra.VANILLA, opts))
+	expPath := []string{"S", "A", "B", "D", "C", "G", "T"}
+	if len(paths) != 2 || paths[0].Weight != 6 {
+		t.Fatalf("Wrong paths: %v", paths)
+	}
+	for i, v := range paths[0].Path {
+		if v.Node != expPath[i] {
+			t.Fatalf("Wrong path: %v", paths[0].Path)
+		}
+	}
+	if paths[1].Weight != 7 {
+		t.Fatalf("Wrong path weight:\nExpected: %d\nGot: %d\n", 7, paths[1].Weight)
+	}
+}
+
+func TestTieBreak(t *testing.T) {
+	g := graphtest.NewGraph()
+	for _, n := range []string{"C", "B", "A"} {
+		g.AddEdge("S", n, "", 1)
+		g.AddEdge(n, "T", "", 1)
+	}
+	paths := Yen(g, "S", "T", 3, dijkstra.SearchFunc(dijkstra.VANILLA, dijkstra.SearchOptions{TieBreak: dijkstra.TieBreakHops}))
+	expNodes := []string{"A", "B", "C"}
+	if len(paths) != len(expNodes) {
+		t.Fatalf("Wrong number of paths:\nExpected: %d\nGot: %d\n", len(expNodes), len(paths))
+	}
+	for k, p := range paths {
+		if p.Path[1].Node != expNodes[k] {
+			t.Fatalf("Wrong path (%d): %v\n", k, p.Path)
+		}
+	}
+}
+
+func TestLoopless(t *testing.T) {
+	g := graphtest.NewGraph()
+	g.AddEdge("S", "A", "", 1)
+	g.AddEdge("A", "S", "", 1)
+	g.AddEdge("S", "T", "", 5)
+	g.AddEdge("A", "T", "", 10)
+	paths := Yen(g, "S", "T", 3, dijkstra.Dijkstra)
+	expWeights := []int{5, 11}
+	if len(paths) != len(expWeights) {
+		t.Fatalf("Wrong number of paths: %v", paths)
+	}
+	for k, p := range paths {
+		if p.Weight != expWeights[k] {
+			t.Fatalf("Wrong path (%d): %v\n", k, p.Path)
+		}
+	}
+}